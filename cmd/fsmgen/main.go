@@ -0,0 +1,69 @@
+// Command fsmgen generates a Go implementation of fsm.Automaton, with the
+// transition table baked into arrays, and a test asserting it agrees with
+// the interpreted engine. It is meant to be run by go:generate:
+//
+//	//go:generate go run modulo_three_advanced/cmd/fsmgen -def machine.json -type Machine
+//
+// The package defaults to $GOPACKAGE, which go generate sets. fsmgen only
+// reads the definition file, never the package it writes into, so generated
+// files can always be rebuilt from scratch.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"modulo_three_advanced/fsm"
+	"modulo_three_advanced/fsm/codegen"
+)
+
+func main() {
+	defPath := flag.String("def", "", "definition file (JSON) to generate from")
+	pkg := flag.String("pkg", os.Getenv("GOPACKAGE"), "package of the generated files")
+	typeName := flag.String("type", "", "name of the generated type (required)")
+	out := flag.String("out", "", "output file; defaults to <type>_gen.go in lower case")
+	withTest := flag.Bool("test", true, "also write <out>_test.go checking agreement with the interpreter")
+	flag.Parse()
+
+	if err := run(*defPath, *pkg, *typeName, *out, *withTest); err != nil {
+		fmt.Fprintln(os.Stderr, "fsmgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(defPath, pkg, typeName, out string, withTest bool) error {
+	if typeName == "" || defPath == "" {
+		return fmt.Errorf("usage: fsmgen -def <file> -type <name> [-pkg <package>] [-out <file>]")
+	}
+
+	// 1. Load the definition.
+	def, err := fsm.LoadDefinition(defPath)
+	if err != nil {
+		return err
+	}
+	source := filepath.Base(defPath)
+
+	// 2. Generate and write the sources.
+	cfg := codegen.Config{Package: pkg, TypeName: typeName, Source: source}
+	if out == "" {
+		out = strings.ToLower(typeName) + "_gen.go"
+	}
+	src, err := codegen.Generate(def, cfg)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(out, src, 0o644); err != nil {
+		return err
+	}
+	if !withTest {
+		return nil
+	}
+	testSrc, err := codegen.GenerateTest(def, cfg)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(strings.TrimSuffix(out, ".go")+"_test.go", testSrc, 0o644)
+}
@@ -0,0 +1,212 @@
+package fsm
+
+import "sort"
+
+// AnalysisReport describes structural properties of an automaton that
+// NewFiniteAutomaton does not reject but that usually indicate a mistake
+// in the definition. Every slice is sorted so reports are deterministic.
+type AnalysisReport struct {
+	// Unreachable lists states that no input can reach from the initial state.
+	Unreachable []string
+	// Dead lists states from which no accepting state can be reached.
+	Dead []string
+	// Sink lists non-accepting states whose every transition loops back to
+	// themselves (trap states). Every sink is also dead.
+	Sink []string
+	// SelfLoopOnly lists accepting states whose every transition loops back
+	// to themselves: once entered, the input is accepted regardless of the rest.
+	SelfLoopOnly []string
+	// UnusedSymbols lists alphabet symbols that never move the automaton to a
+	// different state, i.e. every transition on them is a self-loop.
+	UnusedSymbols []string
+	// Components lists the strongly connected components of the transition
+	// graph, each sorted, ordered by their smallest state name.
+	Components [][]string
+}
+
+// HasFindings reports whether the analysis found anything worth a warning.
+// A single strongly connected component per state is normal and is not counted.
+func (r AnalysisReport) HasFindings() bool {
+	return len(r.Unreachable) > 0 || len(r.Dead) > 0 || len(r.Sink) > 0 ||
+		len(r.SelfLoopOnly) > 0 || len(r.UnusedSymbols) > 0
+}
+
+// -----------------------------------------------------------------------------
+// Generic FSM API Method: Analyze
+// -----------------------------------------------------------------------------
+
+// Analyze inspects the transition graph of fa and reports unreachable, dead,
+// sink and self-loop-only states, inert alphabet symbols and the strongly
+// connected components. Missing transitions are simply treated as absent edges.
+func Analyze(fa *FiniteAutomaton) AnalysisReport {
+	states := sortedKeys(fa.States)
+	alphabet := sortedKeys(fa.Alphabet)
+
+	// Forward and reverse adjacency restricted to declared states.
+	successors := make(map[string][]string, len(states))
+	predecessors := make(map[string][]string, len(states))
+	for _, from := range states {
+		for _, symbol := range alphabet {
+			to, ok := fa.Transitions[from][symbol]
+			if !ok || !fa.States[to] {
+				continue
+			}
+			successors[from] = append(successors[from], to)
+			predecessors[to] = append(predecessors[to], from)
+		}
+	}
+
+	var report AnalysisReport
+
+	// 1. Reachability from q0 (forward search).
+	reachable := search([]string{fa.InitialState}, successors, fa.States)
+	for _, s := range states {
+		if !reachable[s] {
+			report.Unreachable = append(report.Unreachable, s)
+		}
+	}
+
+	// 2. Co-reachability of F (backward search from every accepting state).
+	var accepting []string
+	for _, s := range states {
+		if fa.AcceptingStates[s] {
+			accepting = append(accepting, s)
+		}
+	}
+	live := search(accepting, predecessors, fa.States)
+	for _, s := range states {
+		if !live[s] {
+			report.Dead = append(report.Dead, s)
+		}
+	}
+
+	// 3. Absorbing states: every symbol is defined and loops back.
+	for _, s := range states {
+		if !isAbsorbing(fa, s, alphabet) {
+			continue
+		}
+		if fa.AcceptingStates[s] {
+			report.SelfLoopOnly = append(report.SelfLoopOnly, s)
+		} else {
+			report.Sink = append(report.Sink, s)
+		}
+	}
+
+	// 4. Symbols that never change the current state.
+	for _, symbol := range alphabet {
+		inert := true
+		for _, s := range states {
+			if to, ok := fa.Transitions[s][symbol]; ok && fa.States[to] && to != s {
+				inert = false
+				break
+			}
+		}
+		if inert {
+			report.UnusedSymbols = append(report.UnusedSymbols, symbol)
+		}
+	}
+
+	// 5. Strongly connected components.
+	report.Components = stronglyConnected(states, successors)
+
+	return report
+}
+
+// search returns every declared state reachable from roots along edges.
+func search(roots []string, edges map[string][]string, declared map[string]bool) map[string]bool {
+	seen := make(map[string]bool)
+	queue := make([]string, 0, len(roots))
+	for _, r := range roots {
+		if declared[r] && !seen[r] {
+			seen[r] = true
+			queue = append(queue, r)
+		}
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range edges[current] {
+			if !seen[next] {
+				seen[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return seen
+}
+
+// isAbsorbing reports whether every symbol of the alphabet maps state to itself.
+func isAbsorbing(fa *FiniteAutomaton, state string, alphabet []string) bool {
+	if len(alphabet) == 0 {
+		return false
+	}
+	for _, symbol := range alphabet {
+		if to, ok := fa.Transitions[state][symbol]; !ok || to != state {
+			return false
+		}
+	}
+	return true
+}
+
+// stronglyConnected runs Tarjan's algorithm over the declared states.
+func stronglyConnected(states []string, successors map[string][]string) [][]string {
+	index := 0
+	indices := make(map[string]int, len(states))
+	lowLink := make(map[string]int, len(states))
+	onStack := make(map[string]bool, len(states))
+	var stack []string
+	var components [][]string
+
+	var visit func(v string)
+	visit = func(v string) {
+		indices[v] = index
+		lowLink[v] = index
+		index++
+		stack = append(stack, v)
+		onStack[v] = true
+
+		for _, w := range successors[v] {
+			if _, seen := indices[w]; !seen {
+				visit(w)
+				lowLink[v] = min(lowLink[v], lowLink[w])
+			} else if onStack[w] {
+				lowLink[v] = min(lowLink[v], indices[w])
+			}
+		}
+
+		// v is the root of a component: pop it off the stack.
+		if lowLink[v] == indices[v] {
+			var component []string
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				component = append(component, w)
+				if w == v {
+					break
+				}
+			}
+			sort.Strings(component)
+			components = append(components, component)
+		}
+	}
+
+	for _, s := range states {
+		if _, seen := indices[s]; !seen {
+			visit(s)
+		}
+	}
+
+	sort.Slice(components, func(i, j int) bool { return components[i][0] < components[j][0] })
+	return components
+}
+
+// sortedKeys returns the keys of a set in ascending order.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
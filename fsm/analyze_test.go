@@ -0,0 +1,95 @@
+package fsm
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// -----------------------------------------------------------------------------
+// UNIT TESTS FOR Analyze
+// -----------------------------------------------------------------------------
+
+func TestAnalyze(t *testing.T) {
+	load := func(name string) *FiniteAutomaton {
+		def, err := LoadDefinition(filepath.Join("testdata", name))
+		if err != nil {
+			t.Fatalf("LoadDefinition(%s): %v", name, err)
+		}
+		fa, err := def.Build()
+		if err != nil {
+			t.Fatalf("Build(%s): %v", name, err)
+		}
+		return fa.(*FiniteAutomaton)
+	}
+
+	tests := []struct {
+		name     string
+		fa       *FiniteAutomaton
+		expected AnalysisReport
+	}{
+		{
+			name: "Mod3_Clean",
+			fa:   load("mod3.json"),
+			expected: AnalysisReport{
+				Components: [][]string{{"S0", "S1", "S2"}},
+			},
+		},
+		{
+			name: "TrapAndOrphan",
+			fa:   load("trap.json"),
+			expected: AnalysisReport{
+				Unreachable: []string{"Orphan"},
+				Dead:        []string{"Trap"},
+				Sink:        []string{"Trap"},
+				Components:  [][]string{{"Accept", "SawA", "Start"}, {"Orphan"}, {"Trap"}},
+			},
+		},
+		{
+			name: "AbsorbingAcceptAndInertSymbol",
+			fa: &FiniteAutomaton{
+				States:          map[string]bool{"A": true, "B": true},
+				Alphabet:        map[string]bool{"go": true, "noop": true},
+				InitialState:    "A",
+				AcceptingStates: map[string]bool{"B": true},
+				Transitions: map[string]map[string]string{
+					"A": {"go": "B", "noop": "A"},
+					"B": {"go": "B", "noop": "B"},
+				},
+			},
+			expected: AnalysisReport{
+				SelfLoopOnly:  []string{"B"},
+				UnusedSymbols: []string{"noop"},
+				Components:    [][]string{{"A"}, {"B"}},
+			},
+		},
+		{
+			name: "MissingTransitionsAreIgnored",
+			fa: &FiniteAutomaton{
+				States:          map[string]bool{"A": true, "B": true},
+				Alphabet:        map[string]bool{"x": true},
+				InitialState:    "A",
+				AcceptingStates: map[string]bool{"A": true},
+				Transitions:     map[string]map[string]string{"B": {"x": "Undeclared"}},
+			},
+			expected: AnalysisReport{
+				Unreachable:   []string{"B"},
+				Dead:          []string{"B"},
+				UnusedSymbols: []string{"x"},
+				Components:    [][]string{{"A"}, {"B"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := Analyze(tt.fa)
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("Analyze mismatch.\n got: %+v\nwant: %+v", actual, tt.expected)
+			}
+			if actual.HasFindings() != tt.expected.HasFindings() {
+				t.Errorf("HasFindings: got %t", actual.HasFindings())
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package fsm
+
+import (
+	"errors"
+	"maps"
+	"slices"
+)
+
+// Builder assembles an automaton step by step instead of through the five
+// positional arguments of NewFiniteAutomaton:
+//
+//	fa, err := fsm.NewBuilder().
+//		AddState("S0", "S1").
+//		AddSymbol("0", "1").
+//		SetInitial("S0").
+//		Accept("S1").
+//		On("S0", "1", "S1").
+//		Build(fsm.WithDefaultSink("Trap"))
+//
+// Mistakes are accumulated rather than returned one at a time: Build reports
+// them, together with every issue NewFiniteAutomaton finds, in a single
+// *ValidationError.
+type Builder struct {
+	states      []string
+	alphabet    []string
+	initial     string
+	accepting   []string
+	transitions map[string]map[string]string
+
+	declaredStates  map[string]bool
+	declaredSymbols map[string]bool
+	issues          []Issue // found while building; the rest are left to NewFiniteAutomaton
+}
+
+// NewBuilder returns an empty Builder.
+func NewBuilder() *Builder {
+	return &Builder{
+		transitions:     make(map[string]map[string]string),
+		declaredStates:  make(map[string]bool),
+		declaredSymbols: make(map[string]bool),
+	}
+}
+
+// AddState declares states (Q). Empty and duplicate names are reported by Build.
+func (b *Builder) AddState(states ...string) *Builder {
+	for _, s := range states {
+		switch {
+		case s == "":
+			b.report(Issue{Kind: IssueEmptyState})
+		case b.declaredStates[s]:
+			b.report(Issue{Kind: IssueDuplicateState, State: s})
+		default:
+			b.declaredStates[s] = true
+			b.states = append(b.states, s)
+		}
+	}
+	return b
+}
+
+// AddSymbol declares input symbols (Σ). Empty and duplicate symbols are reported by Build.
+func (b *Builder) AddSymbol(symbols ...string) *Builder {
+	for _, a := range symbols {
+		switch {
+		case a == "":
+			b.report(Issue{Kind: IssueEmptySymbol})
+		case b.declaredSymbols[a]:
+			b.report(Issue{Kind: IssueDuplicateSymbol, Symbol: a})
+		default:
+			b.declaredSymbols[a] = true
+			b.alphabet = append(b.alphabet, a)
+		}
+	}
+	return b
+}
+
+// SetInitial sets the initial state (q0). Setting a different one twice is an error.
+func (b *Builder) SetInitial(state string) *Builder {
+	if b.initial != "" && b.initial != state {
+		b.report(Issue{Kind: IssueConflictingInitial, State: b.initial, Other: state})
+		return b
+	}
+	b.initial = state
+	return b
+}
+
+// Accept marks states as accepting (F).
+func (b *Builder) Accept(states ...string) *Builder {
+	b.accepting = append(b.accepting, states...)
+	return b
+}
+
+// On adds the transition δ(from, symbol) = to. Redefining a transition with a
+// different target is an error.
+func (b *Builder) On(from, symbol, to string) *Builder {
+	row, ok := b.transitions[from]
+	if !ok {
+		row = make(map[string]string)
+		b.transitions[from] = row
+	}
+	if existing, ok := row[symbol]; ok && existing != to {
+		b.report(Issue{Kind: IssueConflictingTransition, State: from, Symbol: symbol, Target: existing, Other: to})
+		return b
+	}
+	row[symbol] = to
+	return b
+}
+
+// Build constructs the automaton with NewFiniteAutomaton, which checks every
+// reference made through the builder. Problems found while building and by
+// that validation are reported at once, in a single *ValidationError.
+func (b *Builder) Build(opts ...Option) (Automaton, error) {
+	// The automaton gets its own copies, so reusing the builder after Build
+	// cannot change an automaton that has already been validated.
+	opts = append([]Option{WithAllErrors()}, opts...)
+	transitions := make(map[string]map[string]string, len(b.transitions))
+	for from, row := range b.transitions {
+		transitions[from] = maps.Clone(row)
+	}
+	fa, err := NewFiniteAutomaton(slices.Clone(b.states), slices.Clone(b.alphabet), b.initial, slices.Clone(b.accepting), transitions, opts...)
+	if len(b.issues) == 0 {
+		return fa, err
+	}
+
+	issues := slices.Clone(b.issues)
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		issues = append(issues, validationErr.Issues...)
+	}
+	return nil, &ValidationError{Issues: issues}
+}
+
+func (b *Builder) report(issue Issue) {
+	b.issues = append(b.issues, issue)
+}
@@ -0,0 +1,177 @@
+package fsm
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// -----------------------------------------------------------------------------
+// UNIT TESTS FOR Builder
+// -----------------------------------------------------------------------------
+
+func TestBuilder_ModThree(t *testing.T) {
+	fa, err := NewBuilder().
+		AddState("S0", "S1", "S2").
+		AddSymbol("0", "1").
+		SetInitial("S0").
+		Accept("S0", "S1", "S2").
+		On("S0", "0", "S0").On("S0", "1", "S1").
+		On("S1", "0", "S2").On("S1", "1", "S0").
+		On("S2", "0", "S1").On("S2", "1", "S2").
+		On("S2", "1", "S2"). // identical redefinition is harmless
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if state, _ := fa.Run("1101"); state != "S1" {
+		t.Errorf("Run(\"1101\"): got %q, want S1", state)
+	}
+}
+
+func TestBuilder_AccumulatesErrors(t *testing.T) {
+	_, err := NewBuilder().
+		AddState("A", "A", "").
+		AddSymbol("x", "x").
+		SetInitial("A").SetInitial("B").
+		Accept("Z").
+		On("A", "x", "A").On("A", "x", "B").
+		On("A", "y", "Q").
+		On("Ghost", "x", "A").
+		Build()
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected a ValidationError, got %v", err)
+	}
+
+	expected := []Issue{
+		{Kind: IssueDuplicateState, State: "A"},
+		{Kind: IssueEmptyState},
+		{Kind: IssueDuplicateSymbol, Symbol: "x"},
+		{Kind: IssueConflictingInitial, State: "A", Other: "B"},
+		{Kind: IssueConflictingTransition, State: "A", Symbol: "x", Target: "A", Other: "B"},
+		{Kind: IssueUnknownAccepting, State: "Z"},
+		{Kind: IssueUnknownSymbol, State: "A", Symbol: "y"},
+		{Kind: IssueUndefinedTarget, State: "A", Symbol: "y", Target: "Q"},
+		{Kind: IssueUndeclaredSource, State: "Ghost"},
+	}
+	if len(validationErr.Issues) != len(expected) {
+		t.Fatalf("Expected %d issues, got %d:\n%v", len(expected), len(validationErr.Issues), err)
+	}
+	for i, issue := range validationErr.Issues {
+		if issue != expected[i] {
+			t.Errorf("Issue %d: got %+v, want %+v", i, issue, expected[i])
+		}
+	}
+	if !strings.Contains(err.Error(), "Transition from 'A' on 'x' defined as both 'A' and 'B'") {
+		t.Errorf("Unexpected message:\n%v", err)
+	}
+
+	// Missing initial state.
+	if _, err := NewBuilder().AddState("A").Build(); err == nil || !strings.Contains(err.Error(), "Initial state not set") {
+		t.Errorf("Expected missing initial state error, got %v", err)
+	}
+}
+
+func TestBuilder_ReuseAfterBuild(t *testing.T) {
+	b := NewBuilder().
+		AddState("A", "B").
+		AddSymbol("x", "y").
+		SetInitial("A").
+		Accept("B").
+		On("A", "x", "B")
+	first, err := b.Build(WithPartialTransitions())
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	// Extending the builder afterwards only affects later builds.
+	b.AddState("C").AddSymbol("z").Accept("C").On("A", "y", "A").On("B", "x", "C")
+	second, err := b.Build(WithPartialTransitions())
+	if err != nil {
+		t.Fatalf("Second Build failed: %v", err)
+	}
+
+	concrete := first.(*FiniteAutomaton)
+	if concrete.States["C"] || concrete.Alphabet["z"] || concrete.IsAccepting("C") {
+		t.Errorf("First automaton gained declarations made after Build: states %v, alphabet %v", concrete.States, concrete.Alphabet)
+	}
+	if state, err := first.Run("x"); err != nil || state != "B" {
+		t.Errorf("First Run(\"x\"): got (%q, %v), want B", state, err)
+	}
+	for _, input := range []string{"y", "xx"} {
+		if _, err := first.Run(input); err == nil {
+			t.Errorf("First Run(%q) should fail on a transition added after Build", input)
+		}
+	}
+	if state, err := second.Run("yxx"); err != nil || state != "C" {
+		t.Errorf("Second Run(\"yxx\"): got (%q, %v), want C", state, err)
+	}
+}
+
+func TestBuilder_PartialAndSinkOptions(t *testing.T) {
+	// "ab" only: every other transition is missing.
+	partial := func() *Builder {
+		return NewBuilder().
+			AddState("Start", "SawA", "Done").
+			AddSymbol("a", "b").
+			SetInitial("Start").
+			Accept("Done").
+			On("Start", "a", "SawA").
+			On("SawA", "b", "Done")
+	}
+
+	// Without an option the incomplete δ is rejected by the engine validation.
+	if _, err := partial().Build(); err == nil || !strings.Contains(err.Error(), "Missing transition") {
+		t.Errorf("Expected completeness error, got %v", err)
+	}
+
+	t.Run("WithPartialTransitions", func(t *testing.T) {
+		fa, err := partial().Build(WithPartialTransitions())
+		if err != nil {
+			t.Fatalf("Build failed: %v", err)
+		}
+		if state, err := fa.Run("ab"); err != nil || !fa.IsAccepting(state) {
+			t.Errorf("Run(\"ab\"): got (%q, %v)", state, err)
+		}
+		var runErr *RunError
+		if _, err := fa.Run("abb"); !errors.As(err, &runErr) {
+			t.Errorf("Run(\"abb\") should fail on the missing transition, got %v", err)
+		}
+	})
+
+	t.Run("WithDefaultSink", func(t *testing.T) {
+		fa, err := partial().Build(WithDefaultSink("Trap"))
+		if err != nil {
+			t.Fatalf("Build failed: %v", err)
+		}
+		concrete := fa.(*FiniteAutomaton)
+		if !concrete.States["Trap"] || concrete.IsAccepting("Trap") {
+			t.Errorf("Expected a non-accepting Trap state, got states %v", concrete.States)
+		}
+		for _, tt := range []struct{ input, state string }{{"ab", "Done"}, {"abb", "Trap"}, {"ba", "Trap"}, {"b", "Trap"}} {
+			if state, err := fa.Run(tt.input); err != nil || state != tt.state {
+				t.Errorf("Run(%q): got (%q, %v), want %q", tt.input, state, err, tt.state)
+			}
+		}
+	})
+
+	t.Run("WithDefaultSink_DoesNotMutateInput", func(t *testing.T) {
+		transitions := map[string]map[string]string{"A": {"x": "A"}}
+		_, err := NewFiniteAutomaton([]string{"A"}, []string{"x", "y"}, "A", []string{"A"}, transitions, WithDefaultSink("Dead"))
+		if err != nil {
+			t.Fatalf("NewFiniteAutomaton failed: %v", err)
+		}
+		if len(transitions["A"]) != 1 || transitions["Dead"] != nil {
+			t.Errorf("Caller's transitions were modified: %v", transitions)
+		}
+	})
+
+	t.Run("PartialStillChecksTargets", func(t *testing.T) {
+		_, err := NewFiniteAutomaton([]string{"A"}, []string{"x"}, "A", nil,
+			map[string]map[string]string{"A": {"x": "Nowhere"}}, WithPartialTransitions())
+		if err == nil || !strings.Contains(err.Error(), "undefined state 'Nowhere'") {
+			t.Errorf("Expected undefined target error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,425 @@
+// Package codegen turns an fsm.Definition into Go source: a state machine
+// with the transition table baked into arrays, implementing fsm.Automaton
+// without any map lookups, plus a test asserting that the generated code
+// agrees with the interpreted fsm.FiniteAutomaton.
+//
+// It is the library behind cmd/fsmgen, which is meant to be run by go:generate.
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	"modulo_three_advanced/fsm"
+)
+
+// Config controls the generated code.
+type Config struct {
+	Package  string // package clause of the generated files
+	TypeName string // name of the generated Automaton type
+	Source   string // where the definition came from, recorded in the file header
+}
+
+// maxTestInputs bounds the exhaustive inputs checked by the generated test.
+const maxTestInputs = 50000
+
+// foreignCandidates are tried, in order, as a symbol outside the alphabet for
+// the generated test; the first one not in the alphabet is used.
+var foreignCandidates = []rune{'#', 'x', '?', '~', '§'}
+
+// model is the data behind both templates.
+type model struct {
+	Config
+	Prefix    string // lower-camel TypeName, prefixes unexported identifiers
+	TestName  string // upper-camel TypeName, names the generated test
+	Def       fsm.Definition
+	States    []string // Q in index order
+	Initial   int
+	Accepting []bool
+	Symbols   []rune  // Σ in index order
+	Delta     [][]int // Delta[state][symbol] = next state index
+	Foreign   rune    // a symbol outside Σ, for the test
+	MaxLength int     // exhaustive test input length
+}
+
+// Generate returns the formatted source of the generated automaton.
+func Generate(def fsm.Definition, cfg Config) ([]byte, error) {
+	m, err := newModel(def, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return render(automatonTemplate, m)
+}
+
+// GenerateTest returns the formatted source of a test checking that the
+// generated automaton agrees with the interpreted one on every input up to a
+// bounded length, including inputs with a symbol outside the alphabet.
+func GenerateTest(def fsm.Definition, cfg Config) ([]byte, error) {
+	m, err := newModel(def, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return render(testTemplate, m)
+}
+
+func newModel(def fsm.Definition, cfg Config) (*model, error) {
+	// 1. Only valid automata can be generated.
+	if _, err := def.Build(); err != nil {
+		return nil, fmt.Errorf("FSM Codegen Error: %w", err)
+	}
+	if !token.IsIdentifier(cfg.Package) || !token.IsIdentifier(cfg.TypeName) {
+		return nil, fmt.Errorf("FSM Codegen Error: Invalid package %q or type name %q", cfg.Package, cfg.TypeName)
+	}
+
+	m := &model{Config: cfg, Def: def, Prefix: withFirst(cfg.TypeName, unicode.ToLower), TestName: withFirst(cfg.TypeName, unicode.ToUpper)}
+
+	// 2. Number the states and symbols in definition order, dropping duplicates.
+	m.States = compactInOrder(def.States)
+	stateIndex := make(map[string]int, len(m.States))
+	for i, s := range m.States {
+		stateIndex[s] = i
+	}
+	m.Initial = stateIndex[def.InitialState]
+	m.Accepting = make([]bool, len(m.States))
+	for _, s := range def.AcceptingStates {
+		m.Accepting[stateIndex[s]] = true
+	}
+
+	// The generated Run switches on runes, so every symbol must be one character.
+	for _, symbol := range compactInOrder(def.Alphabet) {
+		r, size := utf8.DecodeRuneInString(symbol)
+		if size == 0 || size != len(symbol) || r == utf8.RuneError {
+			return nil, fmt.Errorf("FSM Codegen Error: Symbol %q is not a single character", symbol)
+		}
+		m.Symbols = append(m.Symbols, r)
+	}
+
+	// 3. Bake δ into a dense table.
+	m.Delta = make([][]int, len(m.States))
+	for i, s := range m.States {
+		m.Delta[i] = make([]int, len(m.Symbols))
+		for j, r := range m.Symbols {
+			m.Delta[i][j] = stateIndex[def.Transitions[s][string(r)]]
+		}
+	}
+
+	// 4. Pick a foreign symbol and the longest exhaustively testable length.
+	m.Foreign = -1
+	for _, r := range foreignCandidates {
+		if !slices.Contains(m.Symbols, r) {
+			m.Foreign = r
+			break
+		}
+	}
+	width := float64(len(m.Symbols) + 1)
+	m.MaxLength = max(int(math.Log(maxTestInputs)/math.Log(width)), 1)
+	return m, nil
+}
+
+func render(tmpl *template.Template, m *model) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, m); err != nil {
+		return nil, fmt.Errorf("FSM Codegen Error: %w", err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("FSM Codegen Error: generated invalid Go: %w", err)
+	}
+	return src, nil
+}
+
+func compactInOrder(items []string) []string {
+	seen := make(map[string]bool, len(items))
+	var out []string
+	for _, item := range items {
+		if !seen[item] {
+			seen[item] = true
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func withFirst(name string, mapping func(rune) rune) string {
+	r, size := utf8.DecodeRuneInString(name)
+	return string(mapping(r)) + name[size:]
+}
+
+// -----------------------------------------------------------------------------
+// Templates
+// -----------------------------------------------------------------------------
+
+var funcs = template.FuncMap{
+	"quote":     strconv.Quote,
+	"quoteRune": strconv.QuoteRune,
+	"join": func(values any) string {
+		var parts []string
+		switch v := values.(type) {
+		case []int:
+			for _, n := range v {
+				parts = append(parts, strconv.Itoa(n))
+			}
+		case []bool:
+			for _, b := range v {
+				parts = append(parts, strconv.FormatBool(b))
+			}
+		case []string:
+			for _, s := range v {
+				parts = append(parts, strconv.Quote(s))
+			}
+		}
+		return strings.Join(parts, ", ")
+	},
+	"sortedKeys": func(m map[string]string) []string {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		return keys
+	},
+	"sortedRows": func(m map[string]map[string]string) []string {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		return keys
+	},
+}
+
+var automatonTemplate = template.Must(template.New("automaton").Funcs(funcs).Parse(`// Code generated by fsmgen{{if .Source}} from {{.Source}}{{end}}. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"context"
+
+	"modulo_three_advanced/fsm"
+)
+
+// {{.TypeName}} is a generated fsm.Automaton with its transition table baked
+// into arrays. It behaves like the interpreted fsm.FiniteAutomaton built from
+// the same definition.
+type {{.TypeName}} struct{}
+
+var _ fsm.Automaton = {{.TypeName}}{}
+
+const {{.Prefix}}Initial = {{.Initial}}
+
+var {{.Prefix}}States = [...]string{ {{join .States}} }
+
+var {{.Prefix}}Accepting = [...]bool{ {{join .Accepting}} }
+
+// {{.Prefix}}Delta[state][symbol] is the next state.
+var {{.Prefix}}Delta = [...][{{len .Symbols}}]int{
+{{- range .Delta}}
+	{ {{join .}} },
+{{- end}}
+}
+
+// {{.Prefix}}Symbol returns the index of char in the alphabet, or -1.
+func {{.Prefix}}Symbol(char rune) int {
+	switch char {
+{{- range $i, $r := .Symbols}}
+	case {{quoteRune $r}}:
+		return {{$i}}
+{{- end}}
+	}
+	return -1
+}
+
+// {{.Prefix}}CheckInterval is the number of steps between checks of a run's
+// context, as in fsm.FiniteAutomaton; it must be a power of two.
+const {{.Prefix}}CheckInterval = 4096
+
+// Run implements fsm.Automaton.
+func (fa {{.TypeName}}) Run(input string) (string, error) {
+	return fa.RunContext(context.Background(), input)
+}
+
+// RunContext is Run that stops with an *fsm.AbortError of kind
+// fsm.KindCanceled once ctx is done. The context is checked every few
+// thousand symbols.
+func ({{.TypeName}}) RunContext(ctx context.Context, input string) (string, error) {
+	done := ctx.Done() // nil for context.Background(), which is never checked
+	state := {{.Prefix}}Initial
+	steps := 0
+	for pos, char := range input {
+		if done != nil && steps&({{.Prefix}}CheckInterval-1) == 0 {
+			select {
+			case <-done:
+				return "", &fsm.AbortError{Kind: fsm.KindCanceled, State: {{.Prefix}}States[state], Steps: steps, Position: pos, Err: ctx.Err()}
+			default:
+			}
+		}
+		symbol := {{.Prefix}}Symbol(char)
+		if symbol < 0 {
+			return "", &fsm.RunError{Kind: fsm.KindInvalidSymbol, State: {{.Prefix}}States[state], Symbol: string(char), Position: pos}
+		}
+		state = {{.Prefix}}Delta[state][symbol]
+		steps++
+	}
+	return {{.Prefix}}States[state], nil
+}
+
+// Execute implements fsm.Automaton.
+func (fa {{.TypeName}}) Execute(input string) (fsm.RunResult, error) {
+	return fa.ExecuteContext(context.Background(), input)
+}
+
+// ExecuteContext is Execute that stops like RunContext once ctx is done.
+func ({{.TypeName}}) ExecuteContext(ctx context.Context, input string) (fsm.RunResult, error) {
+	done := ctx.Done()
+	var visits [len({{.Prefix}}States)]int
+	state := {{.Prefix}}Initial
+	visits[state]++
+	result := fsm.RunResult{FailedAt: -1}
+	var err error
+	for pos, char := range input {
+		if done != nil && result.Consumed&({{.Prefix}}CheckInterval-1) == 0 {
+			select {
+			case <-done:
+				err = &fsm.AbortError{Kind: fsm.KindCanceled, State: {{.Prefix}}States[state], Steps: result.Consumed, Position: pos, Err: ctx.Err()}
+			default:
+			}
+			if err != nil {
+				result.FailedAt = pos
+				break
+			}
+		}
+		symbol := {{.Prefix}}Symbol(char)
+		if symbol < 0 {
+			err = &fsm.RunError{Kind: fsm.KindInvalidSymbol, State: {{.Prefix}}States[state], Symbol: string(char), Position: pos}
+			result.FailedAt = pos
+			break
+		}
+		state = {{.Prefix}}Delta[state][symbol]
+		visits[state]++
+		result.Consumed++
+	}
+
+	result.FinalState = {{.Prefix}}States[state]
+	result.Visits = make(map[string]int)
+	for i, n := range visits {
+		if n > 0 {
+			result.Visits[{{.Prefix}}States[i]] = n
+		}
+	}
+	if err != nil {
+		return result, err
+	}
+	result.Accepted = {{.Prefix}}Accepting[state]
+	return result, nil
+}
+
+// IsAccepting implements fsm.Automaton.
+func ({{.TypeName}}) IsAccepting(state string) bool {
+	for i, name := range {{.Prefix}}States {
+		if name == state {
+			return {{.Prefix}}Accepting[i]
+		}
+	}
+	return false
+}
+
+// ValidateInput implements fsm.Automaton.
+func ({{.TypeName}}) ValidateInput(input string) bool {
+	for _, char := range input {
+		if {{.Prefix}}Symbol(char) < 0 {
+			return false
+		}
+	}
+	return true
+}
+`))
+
+var testTemplate = template.Must(template.New("test").Funcs(funcs).Parse(`// Code generated by fsmgen{{if .Source}} from {{.Source}}{{end}}. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"modulo_three_advanced/fsm"
+)
+
+// Test{{.TestName}}AgreesWithInterpreter runs every input of up to
+// {{.MaxLength}} symbols, over the alphabet plus a foreign symbol, through both
+// the generated and the interpreted automaton.
+func Test{{.TestName}}AgreesWithInterpreter(t *testing.T) {
+	interpreted, err := fsm.NewFiniteAutomaton(
+		[]string{ {{join .Def.States}} },
+		[]string{ {{join .Def.Alphabet}} },
+		{{quote .Def.InitialState}},
+		[]string{ {{join .Def.AcceptingStates}} },
+		map[string]map[string]string{
+{{- range $from := sortedRows .Def.Transitions}}{{$row := index $.Def.Transitions $from}}
+			{{quote $from}}: { {{- range $symbol := sortedKeys $row}}{{quote $symbol}}: {{quote (index $row $symbol)}}, {{end}}},
+{{- end}}
+		},
+	)
+	if err != nil {
+		t.Fatalf("Failed to build the interpreted automaton: %v", err)
+	}
+	generated := {{.TypeName}}{}
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	symbols := []string{ {{- range .Symbols}}{{quote (printf "%c" .)}}, {{end}}{{if ge .Foreign 0}}{{quote (printf "%c" .Foreign)}}{{end}} }
+	inputs := []string{""}
+	for frontier, length := []string{""}, 1; length <= {{.MaxLength}}; length++ {
+		var next []string
+		for _, prefix := range frontier {
+			for _, symbol := range symbols {
+				next = append(next, prefix+symbol)
+			}
+		}
+		inputs = append(inputs, next...)
+		frontier = next
+	}
+
+	for _, input := range inputs {
+		wantState, wantErr := interpreted.Run(input)
+		gotState, gotErr := generated.Run(input)
+		if gotState != wantState {
+			t.Fatalf("Run(%q): generated %q, interpreted %q", input, gotState, wantState)
+		}
+		var wantRunErr, gotRunErr *fsm.RunError
+		if errors.As(wantErr, &wantRunErr) != errors.As(gotErr, &gotRunErr) || (wantRunErr != nil && *wantRunErr != *gotRunErr) {
+			t.Fatalf("Run(%q): generated error %v, interpreted error %v", input, gotErr, wantErr)
+		}
+		wantResult, _ := interpreted.Execute(input)
+		gotResult, _ := generated.Execute(input)
+		if !reflect.DeepEqual(gotResult, wantResult) {
+			t.Fatalf("Execute(%q): generated %+v, interpreted %+v", input, gotResult, wantResult)
+		}
+		wantResult, wantErr = interpreted.(*fsm.FiniteAutomaton).ExecuteContext(canceled, input)
+		gotResult, gotErr = generated.ExecuteContext(canceled, input)
+		if !reflect.DeepEqual(gotResult, wantResult) || !reflect.DeepEqual(gotErr, wantErr) {
+			t.Fatalf("ExecuteContext(%q) with a canceled context: generated (%+v, %v), interpreted (%+v, %v)", input, gotResult, gotErr, wantResult, wantErr)
+		}
+		if generated.ValidateInput(input) != interpreted.ValidateInput(input) {
+			t.Fatalf("ValidateInput(%q) differs", input)
+		}
+	}
+
+	for _, state := range []string{ {{join .States}}, "" } {
+		if generated.IsAccepting(state) != interpreted.IsAccepting(state) {
+			t.Errorf("IsAccepting(%q) differs", state)
+		}
+	}
+}
+`))
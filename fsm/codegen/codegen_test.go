@@ -0,0 +1,124 @@
+package codegen
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"modulo_three_advanced/fsm"
+)
+
+// -----------------------------------------------------------------------------
+// UNIT TESTS FOR Generate / GenerateTest
+// -----------------------------------------------------------------------------
+
+func loadTrap(t *testing.T) fsm.Definition {
+	t.Helper()
+	def, err := fsm.LoadDefinition(filepath.Join("..", "testdata", "trap.json"))
+	if err != nil {
+		t.Fatalf("LoadDefinition failed: %v", err)
+	}
+	return def
+}
+
+func TestGenerate(t *testing.T) {
+	cfg := Config{Package: "machines", TypeName: "EndsWithAB", Source: "trap.json"}
+	src, err := Generate(loadTrap(t), cfg)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"// Code generated by fsmgen from trap.json. DO NOT EDIT.",
+		"package machines",
+		"type EndsWithAB struct{}",
+		`var endsWithABStates = [...]string{"Start", "SawA", "Accept", "Trap", "Orphan"}`,
+		"var endsWithABAccepting = [...]bool{false, false, true, false, false}",
+		"\t{1, 0, 3},\n", // Start: a -> SawA, b -> Start, c -> Trap
+		"case 'c':\n\t\treturn 2",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("Generated source is missing %q:\n%s", want, src)
+		}
+	}
+
+	testSrc, err := GenerateTest(loadTrap(t), cfg)
+	if err != nil {
+		t.Fatalf("GenerateTest failed: %v", err)
+	}
+	for _, want := range []string{
+		"func TestEndsWithABAgreesWithInterpreter(t *testing.T) {",
+		`symbols := []string{"a", "b", "c", "#"}`,
+		`"Orphan": {"a": "Start", "b": "Orphan", "c": "Orphan"},`,
+	} {
+		if !strings.Contains(string(testSrc), want) {
+			t.Errorf("Generated test is missing %q:\n%s", want, testSrc)
+		}
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	valid := Config{Package: "machines", TypeName: "Machine"}
+
+	invalidDef := loadTrap(t)
+	invalidDef.InitialState = "Nowhere"
+
+	wordDef := fsm.Definition{
+		States:       []string{"A"},
+		Alphabet:     []string{"go"},
+		InitialState: "A",
+		Transitions:  map[string]map[string]string{"A": {"go": "A"}},
+	}
+
+	tests := []struct {
+		name string
+		def  fsm.Definition
+		cfg  Config
+		want string
+	}{
+		{"Invalid definition", invalidDef, valid, "Initial state 'Nowhere'"},
+		{"Multi-character symbol", wordDef, valid, `Symbol "go" is not a single character`},
+		{"Bad type name", loadTrap(t), Config{Package: "machines", TypeName: "my-machine"}, "Invalid package"},
+		{"Bad package", loadTrap(t), Config{Package: "", TypeName: "Machine"}, "Invalid package"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Generate(tt.def, tt.cfg)
+			if err == nil || !strings.HasPrefix(err.Error(), "FSM Codegen Error") || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+// TestGenerate_ModThreeUpToDate fails when the checked-in generated mod3
+// automata no longer match their definition files; run go generate ./mod3 to fix it.
+func TestGenerate_ModThreeUpToDate(t *testing.T) {
+	tests := []struct {
+		source   string
+		typeName string
+		file     string
+	}{
+		{"modthree.json", "generatedModThree", "modthree_gen.go"},
+		{"modthree_lsb.json", "generatedModThreeLSB", "modthree_lsb_gen.go"},
+	}
+	for _, tt := range tests {
+		def, err := fsm.LoadDefinition(filepath.Join("..", "..", "mod3", "testdata", tt.source))
+		if err != nil {
+			t.Fatalf("LoadDefinition failed: %v", err)
+		}
+		src, err := Generate(def, Config{Package: "mod3", TypeName: tt.typeName, Source: tt.source})
+		if err != nil {
+			t.Fatalf("Generate failed: %v", err)
+		}
+		checkedIn, err := os.ReadFile(filepath.Join("..", "..", "mod3", tt.file))
+		if err != nil {
+			t.Fatalf("Reading checked-in source: %v", err)
+		}
+		if !bytes.Equal(src, checkedIn) {
+			t.Errorf("mod3/%s is stale; run go generate ./mod3", tt.file)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package fsm
+
+import (
+	"iter"
+	"math/big"
+	"strings"
+)
+
+// Counting and enumeration of fixed-length inputs. Counts use math/big
+// because the number of inputs grows as |Σ|^n and overflows int64 quickly.
+
+// -----------------------------------------------------------------------------
+// Generic FSM API Methods: Counting
+// -----------------------------------------------------------------------------
+
+// CountByState returns, for every state, how many inputs of exactly n symbols
+// over the alphabet end in that state. Inputs that hit a missing transition
+// are not counted anywhere. The counts sum to at most |Σ|^n. There are no
+// inputs of negative length, so for n < 0 every count is zero.
+func CountByState(fa *FiniteAutomaton, n int) map[string]*big.Int {
+	states := sortedKeys(fa.States)
+	alphabet := sortedKeys(fa.Alphabet)
+
+	// current[q] = number of inputs of the current length ending in q.
+	current := zeroCounts(states)
+	if n >= 0 && fa.States[fa.InitialState] {
+		current[fa.InitialState].SetInt64(1)
+	}
+
+	for step := 0; step < n; step++ {
+		next := zeroCounts(states)
+		for _, from := range states {
+			if current[from].Sign() == 0 {
+				continue
+			}
+			for _, symbol := range alphabet {
+				if to, ok := fa.Transitions[from][symbol]; ok && fa.States[to] {
+					next[to].Add(next[to], current[from])
+				}
+			}
+		}
+		current = next
+	}
+	return current
+}
+
+// CountAccepted returns how many inputs of exactly n symbols fa accepts.
+func CountAccepted(fa *FiniteAutomaton, n int) *big.Int {
+	total := new(big.Int)
+	for state, count := range CountByState(fa, n) {
+		if fa.IsAccepting(state) {
+			total.Add(total, count)
+		}
+	}
+	return total
+}
+
+// -----------------------------------------------------------------------------
+// Generic FSM API Methods: Enumeration
+// -----------------------------------------------------------------------------
+
+// EnumerateAccepted lazily yields every accepted input of exactly n symbols
+// in lexicographic order of the (sorted) alphabet. It yields nothing for n < 0,
+// in agreement with CountAccepted.
+func EnumerateAccepted(fa *FiniteAutomaton, n int) iter.Seq[string] {
+	return enumerate(fa, n, fa.IsAccepting)
+}
+
+// EnumerateEndingIn lazily yields every input of exactly n symbols that ends
+// in target, in lexicographic order of the (sorted) alphabet.
+func EnumerateEndingIn(fa *FiniteAutomaton, n int, target string) iter.Seq[string] {
+	return enumerate(fa, n, func(state string) bool { return state == target })
+}
+
+// enumerate walks the input tree depth first, pruning every prefix that
+// cannot be completed to a matching input, so each yielded string costs O(n).
+func enumerate(fa *FiniteAutomaton, n int, isTarget func(string) bool) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		if n < 0 || !fa.States[fa.InitialState] {
+			return
+		}
+		alphabet := sortedKeys(fa.Alphabet)
+		counts := completionCounts(fa, alphabet, n, isTarget)
+
+		symbols := make([]string, 0, n)
+		var walk func(state string, remaining int) bool
+		walk = func(state string, remaining int) bool {
+			if remaining == 0 {
+				return yield(strings.Join(symbols, ""))
+			}
+			for _, symbol := range alphabet {
+				next, ok := fa.Transitions[state][symbol]
+				if !ok || counts[remaining-1][next] == nil || counts[remaining-1][next].Sign() == 0 {
+					continue
+				}
+				symbols = append(symbols, symbol)
+				keepGoing := walk(next, remaining-1)
+				symbols = symbols[:len(symbols)-1]
+				if !keepGoing {
+					return false
+				}
+			}
+			return true
+		}
+
+		if counts[n][fa.InitialState].Sign() > 0 {
+			walk(fa.InitialState, n)
+		}
+	}
+}
+
+// completionCounts returns a table where counts[k][q] is the number of inputs
+// of exactly k symbols that lead from q to a state satisfying isTarget.
+func completionCounts(fa *FiniteAutomaton, alphabet []string, n int, isTarget func(string) bool) []map[string]*big.Int {
+	states := sortedKeys(fa.States)
+	counts := make([]map[string]*big.Int, n+1)
+
+	counts[0] = zeroCounts(states)
+	for _, s := range states {
+		if isTarget(s) {
+			counts[0][s].SetInt64(1)
+		}
+	}
+
+	for k := 1; k <= n; k++ {
+		counts[k] = zeroCounts(states)
+		for _, from := range states {
+			for _, symbol := range alphabet {
+				if to, ok := fa.Transitions[from][symbol]; ok && fa.States[to] {
+					counts[k][from].Add(counts[k][from], counts[k-1][to])
+				}
+			}
+		}
+	}
+	return counts
+}
+
+// zeroCounts allocates a zero counter for every state.
+func zeroCounts(states []string) map[string]*big.Int {
+	counts := make(map[string]*big.Int, len(states))
+	for _, s := range states {
+		counts[s] = new(big.Int)
+	}
+	return counts
+}
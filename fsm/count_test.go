@@ -0,0 +1,93 @@
+package fsm
+
+import (
+	"math/big"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// -----------------------------------------------------------------------------
+// UNIT TESTS FOR Counting and Enumeration
+// -----------------------------------------------------------------------------
+
+func TestCountAccepted(t *testing.T) {
+	fa := loadTrapFA(t)
+
+	tests := []struct {
+		length   int
+		expected int64
+	}{
+		{-1, 0}, // no inputs of negative length
+		{0, 0},  // "" ends in Start
+		{1, 0},
+		{2, 1}, // "ab"
+		{3, 2}, // "aab", "bab"
+		{4, 4}, // "aaab", "abab", "baab", "bbab"
+	}
+
+	for _, tt := range tests {
+		actual := CountAccepted(fa, tt.length)
+		if actual.Cmp(big.NewInt(tt.expected)) != 0 {
+			t.Errorf("CountAccepted(n=%d): got %s, want %d", tt.length, actual, tt.expected)
+		}
+
+		// The lazy enumeration agrees with the count.
+		enumerated := slices.Collect(EnumerateAccepted(fa, tt.length))
+		if int64(len(enumerated)) != tt.expected {
+			t.Errorf("EnumerateAccepted(n=%d) yielded %d inputs, want %d", tt.length, len(enumerated), tt.expected)
+		}
+	}
+}
+
+func TestCountByState_Totals(t *testing.T) {
+	fa := loadTrapFA(t)
+
+	// A complete DFA distributes all |Σ|^n inputs over its states.
+	const n = 40
+	total := new(big.Int)
+	for _, count := range CountByState(fa, n) {
+		total.Add(total, count)
+	}
+	want := new(big.Int).Exp(big.NewInt(3), big.NewInt(n), nil)
+	if total.Cmp(want) != 0 {
+		t.Errorf("CountByState(n=%d) totals %s, want 3^%d = %s", n, total, n, want)
+	}
+
+	// Negative lengths have no inputs, so not even the initial state is counted.
+	for state, count := range CountByState(fa, -1) {
+		if count.Sign() != 0 {
+			t.Errorf("CountByState(n=-1)[%s]: got %s, want 0", state, count)
+		}
+	}
+}
+
+func TestEnumerate_Order(t *testing.T) {
+	fa := loadTrapFA(t)
+
+	accepted := slices.Collect(EnumerateAccepted(fa, 4))
+	expected := []string{"aaab", "abab", "baab", "bbab"}
+	if !reflect.DeepEqual(accepted, expected) {
+		t.Errorf("EnumerateAccepted(n=4): got %q, want %q", accepted, expected)
+	}
+
+	trapped := slices.Collect(EnumerateEndingIn(fa, 1, "Trap"))
+	if !reflect.DeepEqual(trapped, []string{"c"}) {
+		t.Errorf("EnumerateEndingIn(n=1, Trap): got %q, want [\"c\"]", trapped)
+	}
+
+	// Stopping early must not panic or keep yielding.
+	for input := range EnumerateEndingIn(fa, 3, "Trap") {
+		if input != "aac" {
+			t.Errorf("First input ending in Trap: got %q, want %q", input, "aac")
+		}
+		break
+	}
+
+	if got := slices.Collect(EnumerateEndingIn(fa, 2, "Orphan")); len(got) != 0 {
+		t.Errorf("EnumerateEndingIn(Orphan) should be empty, got %q", got)
+	}
+	if got := slices.Collect(EnumerateAccepted(fa, -1)); len(got) != 0 {
+		t.Errorf("EnumerateAccepted(n=-1) should be empty, got %q", got)
+	}
+}
@@ -0,0 +1,69 @@
+package fsm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Definition is the serialisable form of the 5-tuple (Q, Σ, q0, F, δ).
+// It is the on-disk format read by LoadDefinition, encoded as JSON:
+//
+//	{
+//	  "name": "mod3",
+//	  "states": ["S0", "S1", "S2"],
+//	  "alphabet": ["0", "1"],
+//	  "initialState": "S0",
+//	  "acceptingStates": ["S0", "S1", "S2"],
+//	  "transitions": {"S0": {"0": "S0", "1": "S1"}, ...}
+//	}
+type Definition struct {
+	Name            string                       `json:"name,omitempty"`
+	States          []string                     `json:"states"`
+	Alphabet        []string                     `json:"alphabet"`
+	InitialState    string                       `json:"initialState"`
+	AcceptingStates []string                     `json:"acceptingStates"`
+	Transitions     map[string]map[string]string `json:"transitions"`
+}
+
+// ParseDefinition decodes a JSON definition. Unknown fields are rejected so
+// that typos in a definition file are reported rather than silently ignored.
+func ParseDefinition(data []byte) (Definition, error) {
+	var def Definition
+	if err := decodeStrict(data, &def); err != nil {
+		return Definition{}, fmt.Errorf("FSM Definition Error: %w", err)
+	}
+	return def, nil
+}
+
+// LoadDefinition reads and decodes the definition file at path.
+func LoadDefinition(path string) (Definition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Definition{}, fmt.Errorf("FSM Definition Error: %w", err)
+	}
+	def, err := ParseDefinition(data)
+	if err != nil {
+		return Definition{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return def, nil
+}
+
+// Build validates the definition and constructs the automaton it describes.
+func (d Definition) Build(opts ...Option) (Automaton, error) {
+	return NewFiniteAutomaton(d.States, d.Alphabet, d.InitialState, d.AcceptingStates, d.Transitions, opts...)
+}
+
+// decodeStrict unmarshals a single JSON value, rejecting unknown fields and trailing data.
+func decodeStrict(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.More() {
+		return fmt.Errorf("unexpected data after definition")
+	}
+	return nil
+}
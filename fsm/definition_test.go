@@ -0,0 +1,68 @@
+package fsm
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// -----------------------------------------------------------------------------
+// UNIT TESTS FOR Definition loading
+// -----------------------------------------------------------------------------
+
+func TestLoadDefinition(t *testing.T) {
+	def, err := LoadDefinition(filepath.Join("testdata", "mod3.json"))
+	if err != nil {
+		t.Fatalf("LoadDefinition failed: %v", err)
+	}
+	if def.Name != "mod3" || def.InitialState != "S0" || len(def.States) != 3 {
+		t.Errorf("LoadDefinition decoded unexpected definition: %+v", def)
+	}
+
+	fa, err := def.Build()
+	if err != nil {
+		t.Fatalf("Build failed for a valid definition: %v", err)
+	}
+	if state, _ := fa.Run("1101"); state != "S1" {
+		t.Errorf("Run(\"1101\") on loaded definition: got %q, want %q", state, "S1")
+	}
+}
+
+func TestParseDefinition_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          string
+		errorContains string
+	}{
+		{"MalformedJSON", `{"states": [`, "FSM Definition Error"},
+		{"UnknownField", `{"states": [], "initial": "S0"}`, "unknown field"},
+		{"TrailingData", `{"states": []} {}`, "unexpected data"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDefinition([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.errorContains) {
+				t.Errorf("ParseDefinition(%q): got error %v, want it to contain %q", tt.data, err, tt.errorContains)
+			}
+		})
+	}
+}
+
+func TestLoadDefinition_Errors(t *testing.T) {
+	// Missing file
+	if _, err := LoadDefinition(filepath.Join("testdata", "does-not-exist.json")); err == nil {
+		t.Error("Expected an error for a missing file, got nil")
+	}
+
+	// Invalid content: the error names the offending file.
+	path := filepath.Join(t.TempDir(), "broken.json")
+	if err := os.WriteFile(path, []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := LoadDefinition(path)
+	if err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("Expected error mentioning %q, got %v", path, err)
+	}
+}
@@ -0,0 +1,102 @@
+package fsm
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNoTransition is matched (via errors.Is) by every RunError of kind
+// KindNoTransition: a partial automaton rejected the input because the
+// current state has no transition on a valid symbol.
+var ErrNoTransition = errors.New("FSM Error: No transition")
+
+// Sentinels matched (via errors.Is) by an AbortError of the corresponding
+// kind. A canceled run matches its context's error instead.
+var (
+	ErrStepLimit    = errors.New("FSM Error: Step limit reached")
+	ErrInputTooLong = errors.New("FSM Error: Input too long")
+)
+
+// ErrorKind classifies why Run stopped before consuming the whole input.
+type ErrorKind string
+
+const (
+	// KindMissingRules means the current state has no row in δ at all.
+	KindMissingRules ErrorKind = "missing_rules"
+	// KindInvalidSymbol means the current state has no transition on the symbol.
+	KindInvalidSymbol ErrorKind = "invalid_symbol"
+	// KindNoTransition means a partial automaton has no transition for an
+	// alphabet symbol in the current state, which rejects the input.
+	KindNoTransition ErrorKind = "no_transition"
+
+	// KindCanceled means the run's context was canceled or timed out.
+	KindCanceled ErrorKind = "canceled"
+	// KindStepLimit means the run reached the WithMaxSteps limit.
+	KindStepLimit ErrorKind = "step_limit"
+	// KindInputTooLong means the input exceeds the WithMaxInputLength limit.
+	KindInputTooLong ErrorKind = "input_too_long"
+)
+
+// RunError is returned by Run when execution cannot continue. It keeps the
+// state and symbol involved and the byte offset of the symbol in the input,
+// so callers can use errors.As instead of matching on the message.
+type RunError struct {
+	Kind     ErrorKind
+	State    string // state the automaton was in when it stopped
+	Symbol   string // offending input symbol
+	Position int    // byte offset of Symbol in the input
+}
+
+func (e *RunError) Error() string {
+	switch e.Kind {
+	case KindMissingRules:
+		return fmt.Sprintf("FSM Error: Transition rule missing for state %s", e.State)
+	case KindNoTransition:
+		return fmt.Sprintf("FSM Error: No transition from state %s on symbol '%s' (input rejected by partial automaton)", e.State, e.Symbol)
+	default:
+		return fmt.Sprintf("FSM Error: Invalid input symbol '%s' for state %s", e.Symbol, e.State)
+	}
+}
+
+// Unwrap exposes ErrNoTransition for rejections by a partial automaton.
+func (e *RunError) Unwrap() error {
+	if e.Kind == KindNoTransition {
+		return ErrNoTransition
+	}
+	return nil
+}
+
+// AbortError is returned when a run is stopped before the end of a valid
+// input: by its context or by a WithMaxSteps or WithMaxInputLength limit.
+// It records how far execution got.
+type AbortError struct {
+	Kind     ErrorKind // KindCanceled, KindStepLimit or KindInputTooLong
+	State    string    // state reached when the run stopped
+	Steps    int       // transitions taken
+	Position int       // byte offset of the first unread symbol (symbol index for RunSeq)
+	Limit    int       // the limit reached; 0 for KindCanceled
+	Err      error     // the context's error for KindCanceled
+}
+
+func (e *AbortError) Error() string {
+	switch e.Kind {
+	case KindCanceled:
+		return fmt.Sprintf("FSM Error: Run canceled in state %s after %d steps: %v", e.State, e.Steps, e.Err)
+	case KindInputTooLong:
+		return fmt.Sprintf("FSM Error: Input exceeds the limit of %d", e.Limit)
+	default:
+		return fmt.Sprintf("FSM Error: Step limit of %d reached in state %s at position %d", e.Limit, e.State, e.Position)
+	}
+}
+
+// Unwrap exposes the context's error, ErrStepLimit or ErrInputTooLong.
+func (e *AbortError) Unwrap() error {
+	switch e.Kind {
+	case KindCanceled:
+		return e.Err
+	case KindInputTooLong:
+		return ErrInputTooLong
+	default:
+		return ErrStepLimit
+	}
+}
@@ -0,0 +1,70 @@
+package fsm
+
+import (
+	"context"
+	"errors"
+)
+
+// Run results. Run reports only the final state, leaving callers to work out
+// whether the input was accepted, how much of it was read and where it
+// failed; Execute returns all of that, plus the states visited, in one pass.
+
+// -----------------------------------------------------------------------------
+// Generic FSM API Method: Execute
+// -----------------------------------------------------------------------------
+
+// RunResult describes a run in full, so callers need not re-derive it from
+// the final state.
+type RunResult struct {
+	FinalState string         // state reached; on failure, the state the run stopped in
+	Accepted   bool           // the whole input was consumed and FinalState is accepting
+	Consumed   int            // symbols consumed, i.e. transitions taken
+	FailedAt   int            // byte offset of the symbol the run stopped at; -1 if it did not fail
+	Visits     map[string]int // times each state was entered, the initial state included
+}
+
+// Execute runs input like Run and reports the whole run. On failure the error
+// is the one Run returns, and the result describes the run up to that point.
+func (fa *FiniteAutomaton) Execute(input string) (RunResult, error) {
+	return fa.ExecuteContext(context.Background(), input)
+}
+
+// ExecuteContext is Execute that stops with an *AbortError once ctx is done,
+// like RunContext.
+func (fa *FiniteAutomaton) ExecuteContext(ctx context.Context, input string) (RunResult, error) {
+	result := RunResult{FinalState: fa.InitialState, FailedAt: -1, Visits: map[string]int{fa.InitialState: 1}}
+	_, err := fa.traced(ctx, len(input), func() (string, error) {
+		if err := fa.checkLength(input); err != nil {
+			return "", err
+		}
+		return fa.walk(ctx, fa.tokens(input), result.record)
+	})
+	if err != nil {
+		result.FailedAt = failedAt(err)
+		return result, err
+	}
+	result.Accepted = fa.IsAccepting(result.FinalState)
+	return result, nil
+}
+
+// record is the walk callback of Execute.
+func (r *RunResult) record(step Step) bool {
+	r.FinalState = step.To
+	r.Consumed++
+	r.Visits[step.To]++
+	return true
+}
+
+// failedAt returns the position a *RunError or *AbortError stopped at, and -1
+// for errors raised before the run read any input, such as a length limit.
+func failedAt(err error) int {
+	var runErr *RunError
+	var abortErr *AbortError
+	switch {
+	case errors.As(err, &runErr):
+		return runErr.Position
+	case errors.As(err, &abortErr):
+		return abortErr.Position
+	}
+	return -1
+}
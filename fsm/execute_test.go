@@ -0,0 +1,116 @@
+package fsm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// -----------------------------------------------------------------------------
+// UNIT TESTS FOR Execute
+// -----------------------------------------------------------------------------
+
+func TestFiniteAutomaton_Execute(t *testing.T) {
+	fa := setupPartialFA(t)
+	tests := []struct {
+		name     string
+		input    string
+		expected RunResult
+		errKind  ErrorKind // "" when no error is expected
+	}{
+		{
+			name:     "Empty input stays in the initial state",
+			input:    "",
+			expected: RunResult{FinalState: "Start", FailedAt: -1, Visits: map[string]int{"Start": 1}},
+		},
+		{
+			name:     "Accepted with a loop",
+			input:    "abcc",
+			expected: RunResult{FinalState: "Done", Accepted: true, Consumed: 4, FailedAt: -1, Visits: map[string]int{"Start": 1, "SawA": 1, "Done": 3}},
+		},
+		{
+			name:     "Consumed but not accepting",
+			input:    "a",
+			expected: RunResult{FinalState: "SawA", Consumed: 1, FailedAt: -1, Visits: map[string]int{"Start": 1, "SawA": 1}},
+		},
+		{
+			name:     "Rejected by a missing transition",
+			input:    "abb",
+			expected: RunResult{FinalState: "Done", Consumed: 2, FailedAt: 2, Visits: map[string]int{"Start": 1, "SawA": 1, "Done": 1}},
+			errKind:  KindNoTransition,
+		},
+		{
+			name:     "Invalid symbol",
+			input:    "ax",
+			expected: RunResult{FinalState: "SawA", Consumed: 1, FailedAt: 1, Visits: map[string]int{"Start": 1, "SawA": 1}},
+			errKind:  KindInvalidSymbol,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := fa.Execute(tt.input)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Execute(%q): got %+v, want %+v", tt.input, result, tt.expected)
+			}
+			var runErr *RunError
+			if tt.errKind == "" && err != nil {
+				t.Errorf("Execute(%q): unexpected error %v", tt.input, err)
+			} else if tt.errKind != "" && (!errors.As(err, &runErr) || runErr.Kind != tt.errKind) {
+				t.Errorf("Execute(%q): got error %v, want kind %s", tt.input, err, tt.errKind)
+			}
+
+			// Run agrees on the final state and the error.
+			state, stateErr := fa.Run(tt.input)
+			if (err == nil && state != result.FinalState) || (err == nil) != (stateErr == nil) {
+				t.Errorf("Run(%q) = (%q, %v) disagrees with Execute", tt.input, state, stateErr)
+			}
+		})
+	}
+}
+
+func TestFiniteAutomaton_ExecuteAborted(t *testing.T) {
+	automaton, err := NewFiniteAutomaton([]string{"Even", "Odd"}, []string{"1"}, "Even", []string{"Even"},
+		map[string]map[string]string{"Even": {"1": "Odd"}, "Odd": {"1": "Even"}}, WithMaxSteps(3))
+	if err != nil {
+		t.Fatalf("NewFiniteAutomaton failed: %v", err)
+	}
+	sealed := automaton.(*FiniteAutomaton).Freeze()
+
+	result, err := sealed.Execute("11111")
+	if !errors.Is(err, ErrStepLimit) {
+		t.Errorf("Expected ErrStepLimit, got %v", err)
+	}
+	expected := RunResult{FinalState: "Odd", Consumed: 3, FailedAt: 3, Visits: map[string]int{"Even": 2, "Odd": 2}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Step limit: got %+v, want %+v", result, expected)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err = sealed.ExecuteContext(ctx, "11")
+	if !errors.Is(err, context.Canceled) || result.Consumed != 0 || result.FailedAt != 0 || result.FinalState != "Even" {
+		t.Errorf("Canceled: got (%+v, %v)", result, err)
+	}
+}
+
+func TestFailedAt(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"Run error", &RunError{Kind: KindInvalidSymbol, Position: 4}, 4},
+		{"Wrapped abort", fmt.Errorf("run: %w", &AbortError{Kind: KindCanceled, Position: 2}), 2},
+		{"Other error", errors.New("tracer failed"), -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := failedAt(tt.err); got != tt.expected {
+				t.Errorf("failedAt(%v) = %d, want %d", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
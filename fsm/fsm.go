@@ -1,23 +1,46 @@
 package fsm
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"log/slog"
+	"slices"
+
+	"modulo_three_advanced/tracing"
+)
 
 // Automaton decouples consumers from the concrete implementation details
 // of the Run method, allowing different FSM types to be plugged in.
 type Automaton interface {
 	Run(input string) (finalState string, err error)
+	Execute(input string) (RunResult, error)
 	IsAccepting(state string) bool
 	ValidateInput(input string) bool
 }
 
 // FiniteAutomaton (FA) structure
 // Represents the 5-tuple: (Q, Σ, q0, F, δ)
+//
+// Run and the other read-only methods may be called from many goroutines at
+// once, but nothing guards the exported maps: writing to them (or to the
+// transitions map passed to NewFiniteAutomaton) while a Run is in flight is a
+// data race. Share a Freeze snapshot to rule that out.
 type FiniteAutomaton struct {
 	States          map[string]bool              // Q: Set of states (S0, S1, S2)
 	Alphabet        map[string]bool              // Σ: Input alphabet ('0', '1')
 	InitialState    string                       // q0: Initial state (S0)
 	AcceptingStates map[string]bool              // F: Set of accepting states (S0, S1, S2 for our use case)
 	Transitions     map[string]map[string]string // δ: Transition function: map[CurrentState]map[InputSymbol]NextState
+
+	logger         *slog.Logger   // optional; nil keeps the engine silent
+	logTransitions bool           // log every transition at debug level
+	tracer         tracing.Tracer // optional span instrumentation
+	partial        bool           // δ may be undefined for valid symbols (WithPartialTransitions)
+	tokenizer      Tokenizer      // splits input into symbols; RuneTokenizer when nil
+	maxSteps       int            // WithMaxSteps; 0 means no limit
+	maxInputLength int            // WithMaxInputLength; 0 means no limit
 }
 
 // -----------------------------------------------------------------------------
@@ -26,39 +49,256 @@ type FiniteAutomaton struct {
 
 // Run processes an input string against the FA configuration and returns the final state.
 func (fa *FiniteAutomaton) Run(input string) (finalState string, err error) {
+	return fa.Walk(input, nil)
+}
+
+// Walk runs the input like Run and calls visit with every state entered,
+// starting with the initial state. visit may be nil.
+// Failures are reported as *RunError.
+func (fa *FiniteAutomaton) Walk(input string, visit func(state string)) (finalState string, err error) {
+	return fa.traced(context.Background(), len(input), func() (string, error) {
+		if err := fa.checkLength(input); err != nil {
+			return "", err
+		}
+		return fa.walk(context.Background(), fa.tokens(input), visitStates(fa.InitialState, visit))
+	})
+}
+
+// RunContext is Run that stops with an *AbortError of kind KindCanceled once
+// ctx is done. The context is checked every few thousand symbols, so
+// cancellation is prompt without slowing the transition loop.
+func (fa *FiniteAutomaton) RunContext(ctx context.Context, input string) (finalState string, err error) {
+	return fa.traced(ctx, len(input), func() (string, error) {
+		if err := fa.checkLength(input); err != nil {
+			return "", err
+		}
+		return fa.walk(ctx, fa.tokens(input), nil)
+	})
+}
+
+// checkLength rejects input over the WithMaxInputLength limit up front.
+func (fa *FiniteAutomaton) checkLength(input string) error {
+	if fa.maxInputLength > 0 && len(input) > fa.maxInputLength {
+		return fa.abort(&AbortError{Kind: KindInputTooLong, State: fa.InitialState, Limit: fa.maxInputLength})
+	}
+	return nil
+}
+
+// RunSeq runs a sequence of already split symbols, e.g. read from a channel
+// or a decoder, without building an input string. RunError positions are
+// symbol indexes rather than byte offsets.
+func (fa *FiniteAutomaton) RunSeq(symbols iter.Seq[string]) (finalState string, err error) {
+	return fa.traced(context.Background(), -1, func() (string, error) {
+		return fa.walk(context.Background(), indexed(symbols), nil)
+	})
+}
+
+// RunBytes runs a byte source, feeding each byte as a one-byte symbol like
+// ByteTokenizer. RunError positions are byte indexes.
+func (fa *FiniteAutomaton) RunBytes(input iter.Seq[byte]) (finalState string, err error) {
+	return fa.traced(context.Background(), -1, func() (string, error) {
+		return fa.walk(context.Background(), indexed(func(yield func(string) bool) {
+			for b := range input {
+				if !yield(string([]byte{b})) {
+					return
+				}
+			}
+		}), nil)
+	})
+}
+
+// Step is a single transition observed through Steps.
+type Step struct {
+	From     string
+	Symbol   string
+	To       string // "" when Err is set
+	Position int    // byte offset of Symbol (symbol index for StepsSeq)
+	Err      error  // *RunError or *AbortError on the final step of a rejected input
+}
+
+// Steps lazily yields the transitions taken on input, keyed by step number.
+// A rejected or aborted input ends with a Step whose Err is set. Breaking out of the
+// loop stops execution; nothing is buffered.
+func (fa *FiniteAutomaton) Steps(input string) iter.Seq2[int, Step] {
+	return fa.steps(fa.tokens(input))
+}
+
+// StepsSeq is Steps over a sequence of already split symbols.
+func (fa *FiniteAutomaton) StepsSeq(symbols iter.Seq[string]) iter.Seq2[int, Step] {
+	return fa.steps(indexed(symbols))
+}
+
+func (fa *FiniteAutomaton) steps(tokens iter.Seq2[int, string]) iter.Seq2[int, Step] {
+	return func(yield func(int, Step) bool) {
+		n := 0
+		_, err := fa.walk(context.Background(), tokens, func(step Step) bool {
+			ok := yield(n, step)
+			n++
+			return ok
+		})
+		var runErr *RunError
+		var abortErr *AbortError
+		switch {
+		case errors.As(err, &runErr):
+			yield(n, Step{From: runErr.State, Symbol: runErr.Symbol, Position: runErr.Position, Err: err})
+		case errors.As(err, &abortErr):
+			yield(n, Step{From: abortErr.State, Position: abortErr.Position, Err: err})
+		}
+	}
+}
+
+// traced wraps a run in an "fsm.Run" span, a child of any span in ctx, when
+// a tracer is set. A negative inputLength means the length is not known up front.
+func (fa *FiniteAutomaton) traced(ctx context.Context, inputLength int, run func() (string, error)) (finalState string, err error) {
+	if fa.tracer == nil {
+		return run()
+	}
+
+	_, span := fa.tracer.Start(ctx, "fsm.Run")
+	defer span.End()
+	if inputLength >= 0 {
+		span.SetAttributes(tracing.Int(tracing.KeyInputLength, inputLength))
+	}
+
+	finalState, err = run()
+	if err != nil {
+		span.RecordError(err)
+	} else {
+		span.SetAttributes(tracing.String(tracing.KeyFinalState, finalState))
+	}
+	return finalState, err
+}
+
+// indexed numbers a symbol sequence, so symbol indexes serve as positions.
+func indexed(symbols iter.Seq[string]) iter.Seq2[int, string] {
+	return func(yield func(int, string) bool) {
+		pos := 0
+		for symbol := range symbols {
+			if !yield(pos, symbol) {
+				return
+			}
+			pos++
+		}
+	}
+}
+
+// visitStates adapts a per-state callback to walk's per-step callback,
+// reporting the initial state first. It returns nil when visit is nil.
+func visitStates(initial string, visit func(state string)) func(Step) bool {
+	if visit == nil {
+		return nil
+	}
+	visit(initial)
+	return func(step Step) bool {
+		visit(step.To)
+		return true
+	}
+}
+
+// checkInterval is the number of steps between checks of the run's context.
+// It is a power of two so the check is a mask on the step count.
+const checkInterval = 4096
+
+// walk is the transition loop shared by every Run variant. onStep, if not
+// nil, is called after each transition; returning false stops the walk early.
+func (fa *FiniteAutomaton) walk(ctx context.Context, tokens iter.Seq2[int, string], onStep func(Step) bool) (string, error) {
 	// Start at the initial state
 	currentState := fa.InitialState
+	done := ctx.Done() // nil for context.Background(), which is never checked
+	steps := 0
 
-	for _, char := range input {
-		symbol := string(char)
+	for pos, symbol := range tokens {
+		// 0. Stop early on cancellation or a limit, reporting how far the run got.
+		if done != nil && steps&(checkInterval-1) == 0 {
+			select {
+			case <-done:
+				return "", fa.abort(&AbortError{Kind: KindCanceled, State: currentState, Steps: steps, Position: pos, Err: ctx.Err()})
+			default:
+			}
+		}
+		if fa.maxSteps > 0 && steps >= fa.maxSteps {
+			return "", fa.abort(&AbortError{Kind: KindStepLimit, State: currentState, Steps: steps, Position: pos, Limit: fa.maxSteps})
+		}
+		if fa.maxInputLength > 0 && pos >= fa.maxInputLength {
+			return "", fa.abort(&AbortError{Kind: KindInputTooLong, State: currentState, Steps: steps, Position: pos, Limit: fa.maxInputLength})
+		}
 
 		// 1. Check if the current state exists in the transition map
 		transitionsFromCurrent, ok := fa.Transitions[currentState]
-		if !ok {
-			return "", fmt.Errorf("FSM Error: Transition rule missing for state %s", currentState)
+		if !ok && !fa.partial {
+			if fa.logger != nil {
+				fa.logger.Warn("FSM transition rule missing", "symbol", symbol, "position", pos, "state", currentState)
+			}
+			return "", &RunError{Kind: KindMissingRules, State: currentState, Symbol: symbol, Position: pos}
 		}
 
 		// 2. Check if the input symbol is valid for the current state
 		nextState, ok := transitionsFromCurrent[symbol]
 		if !ok {
-			return "", fmt.Errorf("FSM Error: Invalid input symbol '%s' for state %s", symbol, currentState)
+			kind := KindInvalidSymbol
+			// A partial automaton rejects valid symbols it has no transition for.
+			if fa.partial && fa.Alphabet[symbol] {
+				kind = KindNoTransition
+			}
+			if fa.logger != nil {
+				fa.logger.Warn("FSM input rejected", "kind", kind, "symbol", symbol, "position", pos, "state", currentState)
+			}
+			return "", &RunError{Kind: kind, State: currentState, Symbol: symbol, Position: pos}
 		}
 
 		// 3. Move to the next state
+		if fa.logTransitions {
+			fa.logger.Debug("FSM transition", "from", currentState, "symbol", symbol, "to", nextState, "position", pos)
+		}
+		if onStep != nil && !onStep(Step{From: currentState, Symbol: symbol, To: nextState, Position: pos}) {
+			return nextState, nil
+		}
 		currentState = nextState
+		steps++
 	}
 
 	// The state after the entire string is processed is the final state.
 	return currentState, nil
 }
 
+// abort logs an early stop and returns it as an error.
+func (fa *FiniteAutomaton) abort(e *AbortError) error {
+	if fa.logger != nil {
+		fa.logger.Warn("FSM run aborted", "kind", e.Kind, "state", e.State, "steps", e.Steps, "position", e.Position)
+	}
+	return e
+}
+
+// NewFiniteAutomaton validates the 5-tuple and returns the configured engine.
+// Options such as WithLogger only add behaviour; without them the engine is silent.
 func NewFiniteAutomaton(
 	states []string,
 	alphabet []string,
 	initialState string,
 	acceptingStates []string,
 	transitions map[string]map[string]string,
-) (Automaton, error) {
+	opts ...Option,
+) (automaton Automaton, err error) {
+	o := applyOptions(opts)
+
+	// Each validation step is reported as an event on the constructor span.
+	event := func(string) {}
+	if o.tracer != nil {
+		_, span := o.tracer.Start(context.Background(), "fsm.NewFiniteAutomaton")
+		defer span.End()
+		span.SetAttributes(tracing.Int("fsm.states", len(states)), tracing.Int("fsm.alphabet", len(alphabet)))
+		event = func(name string) { span.AddEvent(name) }
+		defer func() {
+			if err != nil {
+				span.RecordError(err)
+			}
+		}()
+	}
+
+	// Route every missing transition to the sink before validating.
+	if o.defaultSink != "" {
+		states, transitions = withSink(states, alphabet, transitions, o.defaultSink)
+	}
 
 	// Create map representations for O(1) lookups
 	stateSet := make(map[string]bool)
@@ -80,42 +320,86 @@ func NewFiniteAutomaton(
 		InitialState:    initialState,
 		AcceptingStates: acceptingSet,
 		Transitions:     transitions,
+		logger:          o.logger,
+		logTransitions:  o.logger != nil && o.logTransitions,
+		tracer:          o.tracer,
+		partial:         o.partial,
+		tokenizer:       o.tokenizer,
+		maxSteps:        o.maxSteps,
+		maxInputLength:  o.maxInputLength,
 	}
-
-	// 1. Validate Initial State is a member of Q
-	if _, ok := stateSet[initialState]; !ok {
-		return nil, fmt.Errorf("FSM Config Error: Initial state '%s' is not defined in the set of States (Q)", initialState)
+	if o.longestMatch {
+		fa.tokenizer = LongestMatchTokenizer(alphabet)
 	}
 
-	// 2. Validate Accepting States are a subset of Q
-	for _, as := range acceptingStates {
-		if _, ok := stateSet[as]; !ok {
-			return nil, fmt.Errorf("FSM Config Error: Accepting state '%s' is not defined in the set of States (Q)", as)
+	issues := collectIssues(states, alphabet, initialState, acceptingStates, transitions, stateSet, alphaSet, o.partial, o.allErrors, event)
+	if err := issuesToError(issues, o.allErrors); err != nil {
+		if o.logger != nil {
+			o.logger.Error("FSM config validation failed", "error", err)
 		}
+		return nil, err
 	}
 
-	// 3. Validate Transition Completeness (DFA property)
-	// Check that every state on every alphabet symbol has a valid transition defined and leads to a valid state.
-	for _, currentState := range states {
-		transitionsFromCurrent, ok := transitions[currentState]
-		if !ok {
-			return nil, fmt.Errorf("FSM Config Error: Missing transition rules for state '%s' (not in δ)", currentState)
+	// If all checks pass, return the valid FA
+	return fa, nil
+}
+
+// withSink returns copies of states and transitions in which every missing
+// transition leads to sink, and sink loops to itself on every symbol.
+// The caller's map is not modified.
+func withSink(states, alphabet []string, transitions map[string]map[string]string, sink string) ([]string, map[string]map[string]string) {
+	completed := make(map[string]map[string]string, len(transitions)+1)
+	for from, row := range transitions {
+		completed[from] = make(map[string]string, len(row))
+		for symbol, to := range row {
+			completed[from][symbol] = to
 		}
+	}
 
+	withSinkState := append([]string(nil), states...)
+	if !slices.Contains(states, sink) {
+		withSinkState = append(withSinkState, sink)
+	}
+	for _, from := range withSinkState {
+		if completed[from] == nil {
+			completed[from] = make(map[string]string, len(alphabet))
+		}
 		for _, symbol := range alphabet {
-			nextState, ok := transitionsFromCurrent[symbol]
-			if !ok {
-				return nil, fmt.Errorf("FSM Config Error: Missing transition for state '%s' on symbol '%s'", currentState, symbol)
-			}
-			// Check that the resulting nextState is also a member of Q
-			if _, ok := stateSet[nextState]; !ok {
-				return nil, fmt.Errorf("FSM Config Error: Transition from '%s' on '%s' leads to undefined state '%s'", currentState, symbol, nextState)
+			if _, ok := completed[from][symbol]; !ok {
+				completed[from][symbol] = sink
 			}
 		}
 	}
+	return withSinkState, completed
+}
 
-	// If all checks pass, return the valid FA
-	return fa, nil
+// IsPartial reports whether fa was built with WithPartialTransitions.
+func (fa *FiniteAutomaton) IsPartial() bool {
+	return fa.partial
+}
+
+// Complete returns a new automaton in which every missing transition leads
+// to sink, so the result passes the standard completeness validation and
+// never fails on a valid symbol. sink is added as a non-accepting state when
+// it is not already declared. fa itself is not modified; logging and tracing
+// settings carry over.
+func (fa *FiniteAutomaton) Complete(sink string) (*FiniteAutomaton, error) {
+	if sink == "" {
+		return nil, fmt.Errorf("FSM Config Error: Sink state name must not be empty")
+	}
+	states := sortedKeys(fa.States)
+	alphabet := sortedKeys(fa.Alphabet)
+	states, transitions := withSink(states, alphabet, fa.Transitions, sink)
+
+	opts := []Option{WithLogger(fa.logger), WithTracer(fa.tracer), WithTokenizer(fa.tokenizer), WithMaxSteps(fa.maxSteps), WithMaxInputLength(fa.maxInputLength)}
+	if fa.logTransitions {
+		opts = append(opts, WithTransitionLogging())
+	}
+	completed, err := NewFiniteAutomaton(states, alphabet, fa.InitialState, sortedKeys(fa.AcceptingStates), transitions, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return completed.(*FiniteAutomaton), nil
 }
 
 func (fa *FiniteAutomaton) IsAccepting(state string) bool {
@@ -125,16 +409,17 @@ func (fa *FiniteAutomaton) IsAccepting(state string) bool {
 	return true
 }
 
-// Iterate over the input string, which naturally iterates over runes (characters).
+// Iterate over the symbols of the input string, as split by the tokenizer
+// (runes, i.e. characters, by default).
 func (fa *FiniteAutomaton) ValidateInput(input string) bool {
-	for _, char := range input {
-		// Convert the rune to the Symbol type (string) for map lookup.
-		symbol := string(char)
-
+	for pos, symbol := range fa.tokens(input) {
 		// Check for the symbol's existence in the alphabet map (O(1) lookup).
 		// If the symbol is not found, the `exists` variable will be false.
 		if _, exists := fa.Alphabet[symbol]; !exists {
-			// If a single character is not in the alphabet, the input is invalid.
+			if fa.logger != nil {
+				fa.logger.Warn("FSM input rejected by alphabet", "symbol", symbol, "position", pos)
+			}
+			// If a single symbol is not in the alphabet, the input is invalid.
 			return false
 		}
 	}
@@ -142,3 +427,17 @@ func (fa *FiniteAutomaton) ValidateInput(input string) bool {
 	// If the loop completes, every symbol in the input is valid.
 	return true
 }
+
+// Tokens returns the symbols of input, with their byte offsets, as Run sees them.
+func (fa *FiniteAutomaton) Tokens(input string) iter.Seq2[int, string] {
+	return fa.tokens(input)
+}
+
+func (fa *FiniteAutomaton) tokens(input string) iter.Seq2[int, string] {
+	if fa.tokenizer == nil {
+		return defaultTokenizer.Tokens(input)
+	}
+	return fa.tokenizer.Tokens(input)
+}
+
+var defaultTokenizer = RuneTokenizer()
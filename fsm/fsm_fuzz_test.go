@@ -0,0 +1,134 @@
+package fsm
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// Native fuzz targets for the engine and the definition loader. Seed corpora
+// live in testdata/fuzz/<FuzzName>; run e.g. `go test -fuzz=FuzzRun ./fsm`.
+
+func FuzzRun(f *testing.F) {
+	fa := loadTrapFA(f)
+	for _, seed := range []string{"", "ab", "aab", "abc", "xyz", "a\xffb", "ééé"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		state, err := fa.Run(input)
+		valid := fa.ValidateInput(input)
+
+		if !valid && err == nil {
+			t.Fatalf("ValidateInput(%q) = false but Run succeeded in %q", input, state)
+		}
+		if valid && err != nil {
+			t.Fatalf("ValidateInput(%q) = true but Run failed: %v", input, err)
+		}
+		if err == nil && !fa.States[state] {
+			t.Fatalf("Run(%q) ended in undeclared state %q", input, state)
+		}
+	})
+}
+
+func FuzzValidateInput(f *testing.F) {
+	fa := loadTrapFA(f)
+	for _, seed := range []string{"", "abc", "abz", "\x00", "a\xff"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		// Reference: every rune (invalid UTF-8 decodes to U+FFFD) must be a symbol.
+		expected := true
+		for i := 0; i < len(input); {
+			r, size := utf8.DecodeRuneInString(input[i:])
+			if !fa.Alphabet[string(r)] {
+				expected = false
+			}
+			i += size
+		}
+		if actual := fa.ValidateInput(input); actual != expected {
+			t.Fatalf("ValidateInput(%q) = %t, want %t", input, actual, expected)
+		}
+	})
+}
+
+// FuzzNewFiniteAutomaton decodes random bytes into a configuration. Each
+// table byte picks a transition target; values past the declared states
+// produce undefined targets and 0xFF leaves the transition missing.
+func FuzzNewFiniteAutomaton(f *testing.F) {
+	f.Add(uint8(3), uint8(2), uint8(0), uint8(0b111), []byte{0, 1, 2, 0, 1, 2})
+	f.Add(uint8(2), uint8(1), uint8(5), uint8(0b01), []byte{1, 0})
+	f.Add(uint8(2), uint8(2), uint8(0), uint8(0b10), []byte{1, 0xFF, 9, 0})
+	f.Add(uint8(0), uint8(0), uint8(0), uint8(0), []byte{})
+
+	f.Fuzz(func(t *testing.T, nStates, nSymbols, initial, acceptMask uint8, table []byte) {
+		nStates, nSymbols = nStates%8, nSymbols%4
+		states := make([]string, nStates)
+		for i := range states {
+			states[i] = fmt.Sprintf("Q%d", i)
+		}
+		alphabet := make([]string, nSymbols)
+		for i := range alphabet {
+			alphabet[i] = string(rune('a' + i))
+		}
+		var accepting []string
+		for i := range states {
+			if acceptMask&(1<<i) != 0 {
+				accepting = append(accepting, states[i])
+			}
+		}
+		transitions := make(map[string]map[string]string)
+		for i, from := range states {
+			transitions[from] = make(map[string]string)
+			for j, symbol := range alphabet {
+				idx := i*len(alphabet) + j
+				if idx >= len(table) || table[idx] == 0xFF {
+					continue
+				}
+				transitions[from][symbol] = fmt.Sprintf("Q%d", table[idx]%16)
+			}
+		}
+
+		automaton, err := NewFiniteAutomaton(states, alphabet, fmt.Sprintf("Q%d", initial%16), accepting, transitions)
+		if err != nil {
+			return
+		}
+
+		// A configuration that passed validation must run every input over its
+		// alphabet to a declared state.
+		fa := automaton.(*FiniteAutomaton)
+		input := strings.Repeat(strings.Join(alphabet, ""), 3)
+		state, err := fa.Run(input)
+		if err != nil {
+			t.Fatalf("validated automaton failed on %q: %v", input, err)
+		}
+		if !fa.States[state] {
+			t.Fatalf("validated automaton ended in undeclared state %q", state)
+		}
+	})
+}
+
+func FuzzParseDefinition(f *testing.F) {
+	f.Add([]byte(`{"states":["A"],"alphabet":["x"],"initialState":"A","acceptingStates":["A"],"transitions":{"A":{"x":"A"}}}`))
+	f.Add([]byte(`{"states":["A","B"],"alphabet":["x"],"initialState":"B","transitions":{"A":{"x":"B"}}}`))
+	f.Add([]byte(`{"transitions":{"A":null}}`))
+	f.Add([]byte(`[]`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		def, err := ParseDefinition(data)
+		if err != nil {
+			return
+		}
+		automaton, err := def.Build()
+		if err != nil {
+			return
+		}
+		fa := automaton.(*FiniteAutomaton)
+		if _, err := fa.Run(strings.Join(def.Alphabet, "")); err != nil && fa.ValidateInput(strings.Join(def.Alphabet, "")) {
+			t.Fatalf("validated definition failed on its own alphabet: %v", err)
+		}
+		Analyze(fa)
+	})
+}
@@ -1,8 +1,11 @@
 package fsm
 
 import (
+	"context"
 	"testing"
 	"errors"
+	"iter"
+	"slices"
 	"strings"
 )
 
@@ -344,4 +347,331 @@ func TestFiniteAutomaton_ValidateInput(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+// -----------------------------------------------------------------------------
+// 5. UNIT TEST FOR Walk and RunError
+// -----------------------------------------------------------------------------
+
+func TestFiniteAutomaton_Walk(t *testing.T) {
+	fa := setupSimpleFA()
+
+	var visited []string
+	state, err := fa.Walk("abc", func(s string) { visited = append(visited, s) })
+	if err != nil || state != "End" {
+		t.Fatalf("Walk(\"abc\"): got (%q, %v), want (\"End\", nil)", state, err)
+	}
+	if expected := []string{"Start", "Middle", "End", "End"}; strings.Join(visited, ",") != strings.Join(expected, ",") {
+		t.Errorf("Walk visited %v, want %v", visited, expected)
+	}
+
+	// Failures carry structured details.
+	tests := []struct {
+		input    string
+		expected RunError
+	}{
+		{"ab?", RunError{Kind: KindInvalidSymbol, State: "End", Symbol: "?", Position: 2}},
+		{"xb", RunError{Kind: KindMissingRules, State: "Fail", Symbol: "b", Position: 1}},
+		{"aé", RunError{Kind: KindInvalidSymbol, State: "Middle", Symbol: "é", Position: 1}},
+	}
+	for _, tt := range tests {
+		_, err := fa.Walk(tt.input, nil)
+		var runErr *RunError
+		if !errors.As(err, &runErr) || *runErr != tt.expected {
+			t.Errorf("Walk(%q) error: got %#v, want %#v", tt.input, err, tt.expected)
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+// 6. UNIT TEST FOR Partial automata and Complete
+// -----------------------------------------------------------------------------
+
+// setupPartialFA accepts exactly "ab" and "abc...c" without an explicit trap state.
+func setupPartialFA(t *testing.T) *FiniteAutomaton {
+	t.Helper()
+	fa, err := NewFiniteAutomaton(
+		[]string{"Start", "SawA", "Done"},
+		[]string{"a", "b", "c"},
+		"Start",
+		[]string{"Done"},
+		map[string]map[string]string{
+			"Start": {"a": "SawA"},
+			"SawA":  {"b": "Done"},
+			"Done":  {"c": "Done"},
+		},
+		WithPartialTransitions(),
+	)
+	if err != nil {
+		t.Fatalf("Failed to build partial FA: %v", err)
+	}
+	return fa.(*FiniteAutomaton)
+}
+
+func TestFiniteAutomaton_PartialRejection(t *testing.T) {
+	fa := setupPartialFA(t)
+	if !fa.IsPartial() {
+		t.Fatal("Expected IsPartial() to be true")
+	}
+
+	tests := []struct {
+		input         string
+		expectedState string
+		expectedKind  ErrorKind
+		errorContains string
+	}{
+		{"abcc", "Done", "", ""},
+		{"b", "", KindNoTransition, "No transition from state Start on symbol 'b'"},
+		{"abb", "", KindNoTransition, "No transition from state Done on symbol 'b'"},
+		{"az", "", KindInvalidSymbol, "Invalid input symbol 'z' for state SawA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			state, err := fa.Run(tt.input)
+			if state != tt.expectedState {
+				t.Errorf("Run(%q) state: got %q, want %q", tt.input, state, tt.expectedState)
+			}
+			if tt.expectedKind == "" {
+				if err != nil {
+					t.Errorf("Run(%q) unexpected error: %v", tt.input, err)
+				}
+				return
+			}
+			var runErr *RunError
+			if !errors.As(err, &runErr) || runErr.Kind != tt.expectedKind || !strings.Contains(err.Error(), tt.errorContains) {
+				t.Errorf("Run(%q) error: got %v, want kind %s containing %q", tt.input, err, tt.expectedKind, tt.errorContains)
+			}
+			if errors.Is(err, ErrNoTransition) != (tt.expectedKind == KindNoTransition) {
+				t.Errorf("Run(%q): errors.Is(err, ErrNoTransition) mismatch for %v", tt.input, err)
+			}
+		})
+	}
+}
+
+func TestFiniteAutomaton_Complete(t *testing.T) {
+	fa := setupPartialFA(t)
+
+	completed, err := fa.Complete("Dead")
+	if err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	if completed.IsPartial() || !completed.States["Dead"] || completed.IsAccepting("Dead") {
+		t.Errorf("Unexpected completed automaton: partial=%t states=%v", completed.IsPartial(), completed.States)
+	}
+	if fa.States["Dead"] || len(fa.Transitions["Start"]) != 1 {
+		t.Error("Complete must not modify the original automaton")
+	}
+
+	// The completed automaton agrees with the partial one on acceptance.
+	for _, input := range []string{"", "ab", "abccc", "b", "abb", "ccc", "aab"} {
+		partialState, partialErr := fa.Run(input)
+		completeState, completeErr := completed.Run(input)
+		if completeErr != nil {
+			t.Fatalf("Completed Run(%q) failed: %v", input, completeErr)
+		}
+		partialAccepts := partialErr == nil && fa.IsAccepting(partialState)
+		if completed.IsAccepting(completeState) != partialAccepts {
+			t.Errorf("Acceptance of %q differs: partial=%t completed state=%q", input, partialAccepts, completeState)
+		}
+	}
+
+	// The sink is reported by Analyze like a hand-written trap state.
+	if report := Analyze(completed); strings.Join(report.Sink, ",") != "Dead" {
+		t.Errorf("Expected Analyze to report Dead as sink, got %+v", report)
+	}
+
+	if _, err := fa.Complete(""); err == nil {
+		t.Error("Expected an error for an empty sink name")
+	}
+}
+
+// -----------------------------------------------------------------------------
+// 7. UNIT TEST FOR RunSeq, RunBytes and Steps
+// -----------------------------------------------------------------------------
+
+func TestFiniteAutomaton_RunSeq(t *testing.T) {
+	fa := setupSimpleFA()
+
+	// Symbols fed from a channel, as a streaming source would.
+	ch := make(chan string, 4)
+	for _, s := range []string{"a", "b", "c", "c"} {
+		ch <- s
+	}
+	close(ch)
+	state, err := fa.RunSeq(func(yield func(string) bool) {
+		for s := range ch {
+			if !yield(s) {
+				return
+			}
+		}
+	})
+	if err != nil || state != "End" {
+		t.Errorf("RunSeq: got (%q, %v), want End", state, err)
+	}
+
+	// Positions are symbol indexes, whatever the symbol length.
+	_, err = fa.RunSeq(slices.Values([]string{"a", "bb"}))
+	var runErr *RunError
+	if !errors.As(err, &runErr) || runErr.Symbol != "bb" || runErr.Position != 1 {
+		t.Errorf("Expected invalid symbol 'bb' at index 1, got %v", err)
+	}
+
+	state, err = fa.RunBytes(slices.Values([]byte("abc")))
+	if err != nil || state != "End" {
+		t.Errorf("RunBytes: got (%q, %v), want End", state, err)
+	}
+	_, err = fa.RunBytes(slices.Values([]byte("ab\xff")))
+	if !errors.As(err, &runErr) || runErr.Symbol != "\xff" || runErr.Position != 2 {
+		t.Errorf("Expected invalid byte at 2, got %v", err)
+	}
+}
+
+func TestFiniteAutomaton_Steps(t *testing.T) {
+	fa := setupSimpleFA()
+
+	var got []Step
+	for i, step := range fa.Steps("abc") {
+		if i != len(got) {
+			t.Fatalf("Step key %d, want %d", i, len(got))
+		}
+		got = append(got, step)
+	}
+	want := []Step{
+		{From: "Start", Symbol: "a", To: "Middle", Position: 0},
+		{From: "Middle", Symbol: "b", To: "End", Position: 1},
+		{From: "End", Symbol: "c", To: "End", Position: 2},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Steps: got %+v, want %+v", got, want)
+	}
+
+	// A rejected input ends with an error step.
+	got = nil
+	for _, step := range fa.StepsSeq(slices.Values([]string{"a", "z"})) {
+		got = append(got, step)
+	}
+	if len(got) != 2 || got[1].Err == nil || got[1].From != "Middle" || got[1].Symbol != "z" || got[1].To != "" {
+		t.Errorf("Expected a final error step, got %+v", got)
+	}
+
+	// Breaking out stops the walk without consuming the rest of the source.
+	consumed := 0
+	source := func(yield func(string) bool) {
+		for _, s := range []string{"a", "b", "c", "c"} {
+			consumed++
+			if !yield(s) {
+				return
+			}
+		}
+	}
+	for _, step := range fa.StepsSeq(source) {
+		if step.To == "End" {
+			break
+		}
+	}
+	if consumed != 2 {
+		t.Errorf("Expected 2 symbols consumed, got %d", consumed)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// 8. UNIT TEST FOR RunContext and run limits
+// -----------------------------------------------------------------------------
+
+func TestFiniteAutomaton_RunContext(t *testing.T) {
+	fa := setupSimpleFA()
+	if state, err := fa.RunContext(context.Background(), "abc"); err != nil || state != "End" {
+		t.Errorf("RunContext: got (%q, %v), want End", state, err)
+	}
+
+	// An already canceled context stops before the first symbol.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := fa.RunContext(ctx, "abc")
+	var abortErr *AbortError
+	if !errors.As(err, &abortErr) || abortErr.Kind != KindCanceled || abortErr.Steps != 0 || abortErr.State != "Start" || !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a canceled run at the start, got %v", err)
+	}
+
+	// Cancelled mid-run: the stop is noticed at the next check and reports the state reached.
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	cancelling := NewBuilder().AddState("Even", "Odd").AddSymbol("1").SetInitial("Even").
+		On("Even", "1", "Odd").On("Odd", "1", "Even")
+	automaton, err := cancelling.Build(WithTokenizer(TokenizerFunc(func(input string) iter.Seq2[int, string] {
+		return func(yield func(int, string) bool) {
+			for i := range len(input) {
+				if i == checkInterval+1 {
+					cancel()
+				}
+				if !yield(i, input[i:i+1]) {
+					return
+				}
+			}
+		}
+	})))
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	_, err = automaton.(*FiniteAutomaton).RunContext(ctx, strings.Repeat("1", 3*checkInterval+1))
+	if !errors.As(err, &abortErr) || abortErr.Steps != 2*checkInterval || abortErr.Position != 2*checkInterval || abortErr.State != "Even" {
+		t.Errorf("Expected a stop after %d steps in Even, got %+v", 2*checkInterval, err)
+	}
+
+	// Deadlines are reported as such.
+	ctx, cancelTimeout := context.WithTimeout(context.Background(), 0)
+	defer cancelTimeout()
+	if _, err := fa.RunContext(ctx, "a"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestFiniteAutomaton_Limits(t *testing.T) {
+	fa, err := NewFiniteAutomaton([]string{"Start", "Middle", "End"}, []string{"a", "b", "c"}, "Start", []string{"End"},
+		setupSimpleFA().Transitions, WithPartialTransitions(), WithMaxSteps(3), WithMaxInputLength(5))
+	if err != nil {
+		t.Fatalf("NewFiniteAutomaton failed: %v", err)
+	}
+	limited := fa.(*FiniteAutomaton)
+
+	if state, err := limited.Run("abc"); err != nil || state != "End" {
+		t.Errorf("Run(\"abc\") is within the limits, got (%q, %v)", state, err)
+	}
+
+	var abortErr *AbortError
+	_, err = limited.Run("abcc")
+	if !errors.As(err, &abortErr) || abortErr.Kind != KindStepLimit || abortErr.Steps != 3 || abortErr.Position != 3 || abortErr.State != "End" || !errors.Is(err, ErrStepLimit) {
+		t.Errorf("Expected the step limit after 3 steps in End, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "Step limit of 3 reached in state End at position 3") {
+		t.Errorf("Unexpected message: %v", err)
+	}
+
+	// A string input over the length limit is rejected up front, before any
+	// symbol is read; sequences are cut off at the limit.
+	_, err = limited.Run("zzzzzz")
+	if !errors.As(err, &abortErr) || abortErr.Kind != KindInputTooLong || abortErr.Steps != 0 || !errors.Is(err, ErrInputTooLong) {
+		t.Errorf("Expected input too long, got %v", err)
+	}
+	_, err = limited.RunSeq(slices.Values([]string{"a", "b", "c", "c", "c", "c"}))
+	if !errors.Is(err, ErrStepLimit) {
+		t.Errorf("Expected the step limit first, got %v", err)
+	}
+	completed, err := limited.Complete("Sink")
+	if err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	if _, err := completed.Run("abcc"); !errors.Is(err, ErrStepLimit) {
+		t.Errorf("Complete should keep the step limit, got %v", err)
+	}
+
+	// Steps ends with the abort.
+	var last Step
+	for _, step := range limited.Steps("abcc") {
+		last = step
+	}
+	if !errors.Is(last.Err, ErrStepLimit) || last.From != "End" || last.Position != 3 {
+		t.Errorf("Expected a final step-limit step, got %+v", last)
+	}
+}
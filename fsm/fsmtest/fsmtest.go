@@ -0,0 +1,222 @@
+// Package fsmtest provides property-based testing helpers for automata and
+// the calculators built on them: random input generation from an automaton's
+// alphabet, reusable invariants, and shrinking of failing inputs to a
+// minimal counterexample.
+package fsmtest
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+
+	"modulo_three_advanced/fsm"
+)
+
+// Default settings used when a Generator field is left zero.
+const (
+	DefaultIterations = 200
+	DefaultMaxLength  = 64
+)
+
+// foreignCandidates are symbols tried, in order, when generating invalid input.
+// Any candidate that happens to be in the alphabet is skipped.
+var foreignCandidates = []string{"x", "2", "A", " ", "-", "é", "#"}
+
+// Generator produces random inputs over an automaton's alphabet.
+// Inputs are built symbol by symbol so failing inputs can be shrunk per symbol.
+type Generator struct {
+	Iterations  int     // number of inputs checked by ForAll; DefaultIterations if zero
+	MaxLength   int     // maximum number of symbols per input; DefaultMaxLength if zero
+	InvalidRate float64 // probability that an input contains a symbol outside the alphabet
+
+	rng      *rand.Rand
+	alphabet []string
+	foreign  []string
+}
+
+// NewGenerator returns a generator over the alphabet of fa. Seed rng
+// explicitly for reproducible runs.
+func NewGenerator(fa *fsm.FiniteAutomaton, rng *rand.Rand) *Generator {
+	alphabet := make([]string, 0, len(fa.Alphabet))
+	for symbol := range fa.Alphabet {
+		alphabet = append(alphabet, symbol)
+	}
+	slices.Sort(alphabet)
+
+	var foreign []string
+	for _, candidate := range foreignCandidates {
+		if !fa.Alphabet[candidate] {
+			foreign = append(foreign, candidate)
+		}
+	}
+
+	return &Generator{rng: rng, alphabet: alphabet, foreign: foreign}
+}
+
+// Valid returns a random input using only alphabet symbols.
+func (g *Generator) Valid() string {
+	return strings.Join(g.validSymbols(), "")
+}
+
+// Invalid returns a random input containing at least one symbol outside the alphabet.
+func (g *Generator) Invalid() string {
+	return strings.Join(g.invalidSymbols(), "")
+}
+
+// next draws the symbols of the next input according to InvalidRate.
+func (g *Generator) next() []string {
+	if len(g.foreign) > 0 && g.rng.Float64() < g.InvalidRate {
+		return g.invalidSymbols()
+	}
+	return g.validSymbols()
+}
+
+func (g *Generator) validSymbols() []string {
+	if len(g.alphabet) == 0 {
+		return nil
+	}
+	symbols := make([]string, g.rng.IntN(g.maxLength()+1))
+	for i := range symbols {
+		symbols[i] = g.alphabet[g.rng.IntN(len(g.alphabet))]
+	}
+	return symbols
+}
+
+func (g *Generator) invalidSymbols() []string {
+	symbols := g.validSymbols()
+	if len(g.foreign) == 0 {
+		return symbols
+	}
+	foreign := g.foreign[g.rng.IntN(len(g.foreign))]
+	return slices.Insert(symbols, g.rng.IntN(len(symbols)+1), foreign)
+}
+
+func (g *Generator) iterations() int {
+	if g.Iterations > 0 {
+		return g.Iterations
+	}
+	return DefaultIterations
+}
+
+func (g *Generator) maxLength() int {
+	if g.MaxLength > 0 {
+		return g.MaxLength
+	}
+	return DefaultMaxLength
+}
+
+// -----------------------------------------------------------------------------
+// Property runners
+// -----------------------------------------------------------------------------
+
+// ForAll checks prop against Iterations generated inputs. The first failure is
+// shrunk to a minimal input and reported through t.Errorf.
+func ForAll(t testing.TB, g *Generator, prop func(input string) error) {
+	t.Helper()
+	for i := 0; i < g.iterations(); i++ {
+		symbols := g.next()
+		if err := prop(strings.Join(symbols, "")); err != nil {
+			shrunk, shrunkErr := g.shrink(symbols, err, func(s []string) error {
+				return prop(strings.Join(s, ""))
+			})
+			t.Errorf("property failed after %d tests\n  shrunk input: %q\n  original:     %q\n  error: %v",
+				i+1, strings.Join(shrunk, ""), strings.Join(symbols, ""), shrunkErr)
+			return
+		}
+	}
+}
+
+// ForAllPairs checks prop against Iterations generated pairs of inputs,
+// shrinking each side of the first failing pair in turn.
+func ForAllPairs(t testing.TB, g *Generator, prop func(x, y string) error) {
+	t.Helper()
+	for i := 0; i < g.iterations(); i++ {
+		xs, ys := g.next(), g.next()
+		err := prop(strings.Join(xs, ""), strings.Join(ys, ""))
+		if err == nil {
+			continue
+		}
+
+		x, y := xs, ys
+		x, err = g.shrink(x, err, func(s []string) error { return prop(strings.Join(s, ""), strings.Join(y, "")) })
+		y, err = g.shrink(y, err, func(s []string) error { return prop(strings.Join(x, ""), strings.Join(s, "")) })
+		t.Errorf("property failed after %d tests\n  shrunk inputs: (%q, %q)\n  original:      (%q, %q)\n  error: %v",
+			i+1, strings.Join(x, ""), strings.Join(y, ""), strings.Join(xs, ""), strings.Join(ys, ""), err)
+		return
+	}
+}
+
+// shrink greedily minimises a failing symbol sequence: it first removes
+// chunks of decreasing size, then replaces each symbol with the smallest
+// alphabet symbol. It returns the smallest sequence that still fails and its error.
+func (g *Generator) shrink(symbols []string, failure error, fails func([]string) error) ([]string, error) {
+	current := slices.Clone(symbols)
+
+	// 1. Remove chunks, halving the chunk size down to single symbols.
+	// Repeat from the largest chunk whenever a pass made progress.
+	for progressed := true; progressed; {
+		progressed = false
+		for chunk := max(len(current)/2, 1); chunk >= 1; chunk /= 2 {
+			for start := 0; start+chunk <= len(current); {
+				candidate := slices.Concat(current[:start], current[start+chunk:])
+				if err := fails(candidate); err != nil {
+					current, failure, progressed = candidate, err, true
+					continue // retry the same offset against the shorter sequence
+				}
+				start += chunk
+			}
+		}
+	}
+
+	// 2. Simplify symbols towards the first alphabet symbol.
+	if len(g.alphabet) > 0 {
+		simplest := g.alphabet[0]
+		for i := range current {
+			if current[i] == simplest {
+				continue
+			}
+			candidate := slices.Clone(current)
+			candidate[i] = simplest
+			if err := fails(candidate); err != nil {
+				current, failure = candidate, err
+			}
+		}
+	}
+	return current, failure
+}
+
+// -----------------------------------------------------------------------------
+// Reusable invariants
+// -----------------------------------------------------------------------------
+
+// AgreesWithOracle returns a property asserting that a.Run and oracle agree on
+// the final state and on whether the input is rejected with an error.
+func AgreesWithOracle(a fsm.Automaton, oracle func(input string) (string, error)) func(string) error {
+	return func(input string) error {
+		state, err := a.Run(input)
+		wantState, wantErr := oracle(input)
+		if (err != nil) != (wantErr != nil) {
+			return fmt.Errorf("Run(%q) error = %v, oracle error = %v", input, err, wantErr)
+		}
+		if err == nil && state != wantState {
+			return fmt.Errorf("Run(%q) = %q, oracle = %q", input, state, wantState)
+		}
+		return nil
+	}
+}
+
+// InvalidImpliesRunError returns a property asserting that any input rejected
+// by ValidateInput also makes Run fail.
+func InvalidImpliesRunError(a fsm.Automaton) func(string) error {
+	return func(input string) error {
+		if a.ValidateInput(input) {
+			return nil
+		}
+		if state, err := a.Run(input); err == nil {
+			return fmt.Errorf("ValidateInput(%q) = false but Run succeeded in state %q", input, state)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,160 @@
+package fsmtest
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"modulo_three_advanced/fsm"
+)
+
+// recordingT captures failures reported by the property runners so the
+// tests can assert on them without failing themselves.
+type recordingT struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordingT) Helper() {}
+func (r *recordingT) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+// setupParityFA accepts binary strings with an even number of '1' symbols.
+func setupParityFA(t *testing.T) *fsm.FiniteAutomaton {
+	t.Helper()
+	fa, err := fsm.NewFiniteAutomaton(
+		[]string{"Even", "Odd"},
+		[]string{"0", "1"},
+		"Even",
+		[]string{"Even"},
+		map[string]map[string]string{
+			"Even": {"0": "Even", "1": "Odd"},
+			"Odd":  {"0": "Odd", "1": "Even"},
+		},
+	)
+	if err != nil {
+		t.Fatalf("Failed to build parity FA: %v", err)
+	}
+	return fa.(*fsm.FiniteAutomaton)
+}
+
+// parityOracle is an independent implementation of the parity automaton.
+func parityOracle(input string) (string, error) {
+	ones := 0
+	for _, c := range input {
+		switch c {
+		case '0':
+		case '1':
+			ones++
+		default:
+			return "", errors.New("invalid symbol")
+		}
+	}
+	if ones%2 == 0 {
+		return "Even", nil
+	}
+	return "Odd", nil
+}
+
+// -----------------------------------------------------------------------------
+// 1. UNIT TESTS FOR Generator
+// -----------------------------------------------------------------------------
+
+func TestGenerator_ValidAndInvalid(t *testing.T) {
+	fa := setupParityFA(t)
+	g := NewGenerator(fa, rand.New(rand.NewPCG(1, 1)))
+	g.MaxLength = 16
+
+	for i := 0; i < 100; i++ {
+		if valid := g.Valid(); !fa.ValidateInput(valid) || len(valid) > 16 {
+			t.Fatalf("Valid() produced %q", valid)
+		}
+		if invalid := g.Invalid(); fa.ValidateInput(invalid) {
+			t.Fatalf("Invalid() produced valid input %q", invalid)
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+// 2. UNIT TESTS FOR Invariants and Runners
+// -----------------------------------------------------------------------------
+
+func TestInvariants_Hold(t *testing.T) {
+	fa := setupParityFA(t)
+	g := NewGenerator(fa, rand.New(rand.NewPCG(2, 2)))
+	g.InvalidRate = 0.3
+
+	ForAll(t, g, AgreesWithOracle(fa, parityOracle))
+	ForAll(t, g, InvalidImpliesRunError(fa))
+}
+
+func TestForAll_ShrinksCounterexample(t *testing.T) {
+	fa := setupParityFA(t)
+	g := NewGenerator(fa, rand.New(rand.NewPCG(3, 3)))
+
+	// A deliberately false property: "no input contains two '1' symbols".
+	rec := &recordingT{TB: t}
+	ForAll(rec, g, func(input string) error {
+		if strings.Count(input, "1") >= 2 {
+			return fmt.Errorf("found %d ones", strings.Count(input, "1"))
+		}
+		return nil
+	})
+
+	if len(rec.failures) != 1 {
+		t.Fatalf("Expected exactly one reported failure, got %d", len(rec.failures))
+	}
+	if !strings.Contains(rec.failures[0], `shrunk input: "11"`) {
+		t.Errorf("Expected counterexample to shrink to \"11\", got:\n%s", rec.failures[0])
+	}
+}
+
+func TestForAllPairs_ShrinksBothSides(t *testing.T) {
+	fa := setupParityFA(t)
+	g := NewGenerator(fa, rand.New(rand.NewPCG(4, 4)))
+
+	// False property: "x and y never both contain a '1'".
+	rec := &recordingT{TB: t}
+	ForAllPairs(rec, g, func(x, y string) error {
+		if strings.Contains(x, "1") && strings.Contains(y, "1") {
+			return errors.New("both contain 1")
+		}
+		return nil
+	})
+
+	if len(rec.failures) != 1 || !strings.Contains(rec.failures[0], `shrunk inputs: ("1", "1")`) {
+		t.Errorf("Expected pair to shrink to (\"1\", \"1\"), got: %v", rec.failures)
+	}
+}
+
+func TestInvariants_DetectBrokenAutomaton(t *testing.T) {
+	fa := setupParityFA(t)
+	g := NewGenerator(fa, rand.New(rand.NewPCG(5, 5)))
+
+	// Swap the transitions on '1' from Odd: now "11" ends in Odd.
+	fa.Transitions["Odd"]["1"] = "Odd"
+	rec := &recordingT{TB: t}
+	ForAll(rec, g, AgreesWithOracle(fa, parityOracle))
+	if len(rec.failures) != 1 || !strings.Contains(rec.failures[0], `shrunk input: "11"`) {
+		t.Errorf("Expected oracle disagreement shrunk to \"11\", got: %v", rec.failures)
+	}
+
+	// An automaton whose ValidateInput rejects something Run accepts.
+	lenient := &fsm.FiniteAutomaton{
+		Alphabet:     map[string]bool{"0": true},
+		InitialState: "Q",
+		Transitions:  map[string]map[string]string{"Q": {"0": "Q", "1": "Q"}},
+	}
+	g = NewGenerator(lenient, rand.New(rand.NewPCG(6, 6)))
+	g.InvalidRate = 1
+	rec = &recordingT{TB: t}
+	ForAll(rec, g, func(input string) error {
+		return InvalidImpliesRunError(lenient)(strings.ReplaceAll(input, "x", "1"))
+	})
+	if len(rec.failures) != 1 {
+		t.Errorf("Expected InvalidImpliesRunError to report a failure, got: %v", rec.failures)
+	}
+}
@@ -0,0 +1,101 @@
+package fsm
+
+import (
+	"log/slog"
+
+	"modulo_three_advanced/tracing"
+)
+
+// Option configures optional behaviour of NewFiniteAutomaton.
+type Option func(*options)
+
+// options collects the settings applied by Option values.
+type options struct {
+	logger         *slog.Logger
+	logTransitions bool
+	tracer         tracing.Tracer
+	partial        bool
+	defaultSink    string
+	allErrors      bool
+	tokenizer      Tokenizer
+	longestMatch   bool
+	maxSteps       int
+	maxInputLength int
+}
+
+func applyOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithLogger logs config validation failures (error level) and rejected
+// input symbols with their byte position (warn level) to logger.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) { o.logger = logger }
+}
+
+// WithTransitionLogging additionally logs every transition at debug level.
+// It has no effect without WithLogger.
+func WithTransitionLogging() Option {
+	return func(o *options) { o.logTransitions = true }
+}
+
+// WithTracer records a span for NewFiniteAutomaton, with one event per
+// validation step, and a span for every Run/Walk carrying the input length,
+// final state and error. Run has no context, so its spans are roots;
+// RunContext spans are children of the span in its context.
+func WithTracer(tracer tracing.Tracer) Option {
+	return func(o *options) { o.tracer = tracer }
+}
+
+// WithPartialTransitions accepts a transition function that is not defined for
+// every (state, symbol) pair. Defined transitions must still lead to declared
+// states; Run fails when it reaches a missing transition.
+func WithPartialTransitions() Option {
+	return func(o *options) { o.partial = true }
+}
+
+// WithDefaultSink completes the transition function by routing every missing
+// transition to sink, which loops to itself on every symbol. When sink is not
+// already declared it is added to Q as a non-accepting (trap) state.
+func WithDefaultSink(sink string) Option {
+	return func(o *options) { o.defaultSink = sink }
+}
+
+// WithAllErrors makes validation collect every configuration issue instead of
+// stopping at the first: NewFiniteAutomaton then returns a *ValidationError.
+// This mode additionally reports transition rows for undeclared states and
+// transitions on symbols outside the alphabet.
+func WithAllErrors() Option {
+	return func(o *options) { o.allErrors = true }
+}
+
+// WithTokenizer sets how Run, Walk and ValidateInput split input into
+// symbols. The default is RuneTokenizer, one symbol per character.
+func WithTokenizer(t Tokenizer) Option {
+	return func(o *options) { o.tokenizer = t }
+}
+
+// WithLongestMatch tokenizes input with a LongestMatchTokenizer over the
+// automaton's own alphabet, so multi-character symbols such as "10" or
+// "<EOF>" can be used without repeating the alphabet. It overrides WithTokenizer.
+func WithLongestMatch() Option {
+	return func(o *options) { o.longestMatch = true }
+}
+
+// WithMaxSteps stops every run after n transitions with an *AbortError of
+// kind KindStepLimit, so untrusted input cannot make Run work indefinitely.
+// n <= 0 means no limit, the default.
+func WithMaxSteps(n int) Option {
+	return func(o *options) { o.maxSteps = n }
+}
+
+// WithMaxInputLength rejects input longer than n bytes (n symbols for
+// RunSeq) with an *AbortError of kind KindInputTooLong. A string input is
+// rejected before any symbol is read. n <= 0 means no limit, the default.
+func WithMaxInputLength(n int) Option {
+	return func(o *options) { o.maxInputLength = n }
+}
@@ -0,0 +1,122 @@
+package fsm
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"modulo_three_advanced/tracing"
+)
+
+// newTestLogger returns a logger writing text records at debug level into buf.
+func newTestLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+// -----------------------------------------------------------------------------
+// UNIT TESTS FOR Logging options
+// -----------------------------------------------------------------------------
+
+func TestWithLogger(t *testing.T) {
+	states := []string{"S0", "S1"}
+	alphabet := []string{"0", "1"}
+	transitions := map[string]map[string]string{
+		"S0": {"0": "S0", "1": "S1"},
+		"S1": {"0": "S1", "1": "S0"},
+	}
+
+	t.Run("ConfigValidationFailure", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, err := NewFiniteAutomaton(states, alphabet, "S9", states, transitions, WithLogger(newTestLogger(&buf)))
+		if err == nil {
+			t.Fatal("Expected a validation error")
+		}
+		if out := buf.String(); !strings.Contains(out, "level=ERROR") || !strings.Contains(out, "Initial state 'S9'") {
+			t.Errorf("Expected validation failure to be logged, got:\n%s", out)
+		}
+	})
+
+	t.Run("InvalidSymbolWithPosition", func(t *testing.T) {
+		var buf bytes.Buffer
+		fa, _ := NewFiniteAutomaton(states, alphabet, "S0", states, transitions, WithLogger(newTestLogger(&buf)))
+		fa.ValidateInput("01x1")
+		fa.Run("1é0")
+		out := buf.String()
+		for _, want := range []string{"symbol=x position=2", "symbol=é position=1 state=S1"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("Expected log to contain %q, got:\n%s", want, out)
+			}
+		}
+		if strings.Contains(out, "FSM transition ") {
+			t.Errorf("Transitions must not be logged without WithTransitionLogging:\n%s", out)
+		}
+	})
+
+	t.Run("TransitionLogging", func(t *testing.T) {
+		var buf bytes.Buffer
+		fa, _ := NewFiniteAutomaton(states, alphabet, "S0", states, transitions,
+			WithLogger(newTestLogger(&buf)), WithTransitionLogging())
+		fa.Run("10")
+		out := buf.String()
+		if strings.Count(out, "level=DEBUG msg=\"FSM transition\"") != 2 ||
+			!strings.Contains(out, "from=S0 symbol=1 to=S1 position=0") {
+			t.Errorf("Expected two transition records, got:\n%s", out)
+		}
+	})
+
+	t.Run("SilentByDefault", func(t *testing.T) {
+		// Transition logging without a logger is a no-op and must not panic.
+		fa, err := NewFiniteAutomaton(states, alphabet, "S0", states, transitions, WithTransitionLogging())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, err := fa.Run("1x"); err == nil {
+			t.Error("Expected Run to fail on 'x'")
+		}
+	})
+}
+
+// -----------------------------------------------------------------------------
+// UNIT TESTS FOR Tracing option
+// -----------------------------------------------------------------------------
+
+func TestWithTracer(t *testing.T) {
+	rec := tracing.NewRecorder()
+	states := []string{"S0", "S1"}
+	transitions := map[string]map[string]string{
+		"S0": {"0": "S0", "1": "S1"},
+		"S1": {"0": "S1", "1": "S0"},
+	}
+
+	fa, err := NewFiniteAutomaton(states, []string{"0", "1"}, "S0", states, transitions, WithTracer(rec))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	fa.Run("101")
+	fa.Run("1x")
+	_, err = NewFiniteAutomaton(states, []string{"0", "1"}, "S0", []string{"S7"}, transitions, WithTracer(rec))
+	if err == nil {
+		t.Fatal("Expected validation error")
+	}
+
+	spans := rec.Spans()
+	if len(spans) != 4 {
+		t.Fatalf("Expected 4 spans, got %d: %+v", len(spans), spans)
+	}
+
+	ctor := spans[0]
+	if ctor.Name != "fsm.NewFiniteAutomaton" || len(ctor.Events) != 3 || ctor.Events[2].Name != "validate.transitions" || ctor.Err != nil {
+		t.Errorf("Unexpected constructor span: %+v", ctor)
+	}
+	if ok := spans[1]; ok.Name != "fsm.Run" || ok.Attributes[tracing.KeyInputLength] != 3 || ok.Attributes[tracing.KeyFinalState] != "S0" {
+		t.Errorf("Unexpected successful Run span: %+v", ok)
+	}
+	if bad := spans[2]; bad.Err == nil || bad.Attributes[tracing.KeyFinalState] != nil {
+		t.Errorf("Unexpected failed Run span: %+v", bad)
+	}
+	// Validation stops at the accepting states step.
+	if failed := spans[3]; failed.Err == nil || len(failed.Events) != 2 {
+		t.Errorf("Unexpected failed constructor span: %+v", failed)
+	}
+}
@@ -0,0 +1,130 @@
+package fsm
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Product construction: one automaton that runs several automata over the
+// same alphabet in lockstep. Its states are tuples of component states, so a
+// single pass over the input yields the final state of every component.
+
+// -----------------------------------------------------------------------------
+// Generic FSM API Methods: Product
+// -----------------------------------------------------------------------------
+
+// ProductAutomaton is the automaton built by Product. It runs like any other
+// FiniteAutomaton; Components maps its states back to the component states.
+type ProductAutomaton struct {
+	*FiniteAutomaton
+	components map[string][]string
+}
+
+// Product returns the product of automata, which must share one alphabet.
+// Only tuples reachable from the tuple of initial states become states, named
+// like "(S0,R1)". A tuple is accepting when every component accepts, so the
+// product recognises the intersection of the components' languages. When a
+// component has no transition on a symbol, neither does the tuple, and the
+// product is built with WithPartialTransitions. opts are passed on to
+// NewFiniteAutomaton.
+func Product(automata []*FiniteAutomaton, opts ...Option) (*ProductAutomaton, error) {
+	if len(automata) == 0 {
+		return nil, fmt.Errorf("FSM Config Error: Product needs at least one automaton")
+	}
+	alphabet := sortedKeys(automata[0].Alphabet)
+	for i, fa := range automata[1:] {
+		if !maps.Equal(fa.Alphabet, automata[0].Alphabet) {
+			return nil, fmt.Errorf("FSM Config Error: Alphabet of automaton %d differs from automaton 0", i+1)
+		}
+	}
+
+	// 1. Breadth-first search over reachable tuples, naming each one.
+	initial := make([]string, len(automata))
+	for i, fa := range automata {
+		initial[i] = fa.InitialState
+	}
+	p := &ProductAutomaton{components: make(map[string][]string)}
+	var states, accepting []string
+	transitions := make(map[string]map[string]string)
+	partial := false
+
+	visit := func(tuple []string) (string, bool, error) {
+		name := productStateName(tuple)
+		if seen, ok := p.components[name]; ok {
+			if !slices.Equal(seen, tuple) {
+				return "", false, fmt.Errorf("FSM Config Error: Product state name '%s' is ambiguous", name)
+			}
+			return name, false, nil
+		}
+		p.components[name] = tuple
+		states = append(states, name)
+		if allAccepting(automata, tuple) {
+			accepting = append(accepting, name)
+		}
+		return name, true, nil
+	}
+
+	start, _, _ := visit(initial)
+	queue := [][]string{initial}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		from := productStateName(current)
+		transitions[from] = make(map[string]string, len(alphabet))
+
+	symbols:
+		for _, symbol := range alphabet {
+			// 2. Step every component; a missing transition in any of them leaves the tuple without one.
+			next := make([]string, len(automata))
+			for i, fa := range automata {
+				to, ok := fa.Transitions[current[i]][symbol]
+				if !ok {
+					partial = true
+					continue symbols
+				}
+				next[i] = to
+			}
+
+			to, isNew, err := visit(next)
+			if err != nil {
+				return nil, err
+			}
+			transitions[from][symbol] = to
+			if isNew {
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	// 3. Build and validate the product like any other automaton.
+	if partial {
+		opts = append([]Option{WithPartialTransitions()}, opts...)
+	}
+	fa, err := NewFiniteAutomaton(states, alphabet, start, accepting, transitions, opts...)
+	if err != nil {
+		return nil, err
+	}
+	p.FiniteAutomaton = fa.(*FiniteAutomaton)
+	return p, nil
+}
+
+// Components returns the component states of a product state, in the order
+// the automata were passed to Product, or nil if state is not a product state.
+func (p *ProductAutomaton) Components(state string) []string {
+	return p.components[state]
+}
+
+func productStateName(tuple []string) string {
+	return "(" + strings.Join(tuple, ",") + ")"
+}
+
+func allAccepting(automata []*FiniteAutomaton, tuple []string) bool {
+	for i, fa := range automata {
+		if !fa.IsAccepting(tuple[i]) {
+			return false
+		}
+	}
+	return true
+}
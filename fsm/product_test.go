@@ -0,0 +1,145 @@
+package fsm
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// -----------------------------------------------------------------------------
+// UNIT TESTS FOR Product
+// -----------------------------------------------------------------------------
+
+// parity accepts binary inputs with an even number of the given symbol.
+func parity(t *testing.T, symbol string) *FiniteAutomaton {
+	t.Helper()
+	other := map[string]string{"0": "1", "1": "0"}[symbol]
+	fa, err := NewBuilder().
+		AddState("Even", "Odd").
+		AddSymbol("0", "1").
+		SetInitial("Even").
+		Accept("Even").
+		On("Even", symbol, "Odd").On("Odd", symbol, "Even").
+		On("Even", other, "Even").On("Odd", other, "Odd").
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	return fa.(*FiniteAutomaton)
+}
+
+func TestProduct(t *testing.T) {
+	zeros, ones := parity(t, "0"), parity(t, "1")
+	p, err := Product([]*FiniteAutomaton{zeros, ones})
+	if err != nil {
+		t.Fatalf("Product failed: %v", err)
+	}
+
+	if len(p.States) != 4 || p.InitialState != "(Even,Even)" {
+		t.Errorf("Expected 4 states from (Even,Even), got %v from %q", p.States, p.InitialState)
+	}
+	for _, input := range []string{"", "0", "1", "01", "0011", "0110", "10101"} {
+		state, err := p.Run(input)
+		if err != nil {
+			t.Fatalf("Run(%q) failed: %v", input, err)
+		}
+		want := []string{"Even", "Even"}
+		if strings.Count(input, "0")%2 == 1 {
+			want[0] = "Odd"
+		}
+		if strings.Count(input, "1")%2 == 1 {
+			want[1] = "Odd"
+		}
+		if got := p.Components(state); !reflect.DeepEqual(got, want) {
+			t.Errorf("Run(%q): components %v, want %v", input, got, want)
+		}
+		if p.IsAccepting(state) != (want[0] == "Even" && want[1] == "Even") {
+			t.Errorf("Run(%q): IsAccepting(%q) should hold only when both components accept", input, state)
+		}
+	}
+	if p.Components("(Nope)") != nil {
+		t.Error("Components of an unknown state should be nil")
+	}
+}
+
+func TestProduct_OnlyReachableTuples(t *testing.T) {
+	// The product of an automaton with itself never leaves the diagonal.
+	zeros := parity(t, "0")
+	p, err := Product([]*FiniteAutomaton{zeros, zeros})
+	if err != nil {
+		t.Fatalf("Product failed: %v", err)
+	}
+	if len(p.States) != 2 || !p.States["(Even,Even)"] || !p.States["(Odd,Odd)"] {
+		t.Errorf("Expected only the diagonal states, got %v", p.States)
+	}
+	if report := Analyze(p.FiniteAutomaton); len(report.Unreachable) != 0 {
+		t.Errorf("Expected no unreachable states, got %v", report.Unreachable)
+	}
+}
+
+func TestProduct_Partial(t *testing.T) {
+	// "ab" only: the product keeps the component's missing transitions.
+	ab, err := NewBuilder().
+		AddState("Start", "SawA", "Done").
+		AddSymbol("a", "b").
+		SetInitial("Start").
+		Accept("Done").
+		On("Start", "a", "SawA").
+		On("SawA", "b", "Done").
+		Build(WithPartialTransitions())
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	p, err := Product([]*FiniteAutomaton{ab.(*FiniteAutomaton)})
+	if err != nil {
+		t.Fatalf("Product failed: %v", err)
+	}
+	if !p.IsPartial() {
+		t.Error("Expected a partial product")
+	}
+	if state, err := p.Run("ab"); err != nil || !p.IsAccepting(state) {
+		t.Errorf("Run(\"ab\"): got (%q, %v)", state, err)
+	}
+	if _, err := p.Run("b"); err == nil {
+		t.Error("Run(\"b\") should fail on the missing transition")
+	}
+}
+
+func TestProduct_Errors(t *testing.T) {
+	if _, err := Product(nil); err == nil {
+		t.Error("Expected an error for no automata")
+	}
+
+	letters, err := NewBuilder().AddState("A").AddSymbol("a").SetInitial("A").On("A", "a", "A").Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	_, err = Product([]*FiniteAutomaton{parity(t, "0"), letters.(*FiniteAutomaton)})
+	if err == nil || !strings.Contains(err.Error(), "Alphabet of automaton 1 differs") {
+		t.Errorf("Expected an alphabet mismatch error, got %v", err)
+	}
+
+	// Commas in state names can make two tuples print alike.
+	comma, err := NewBuilder().
+		AddState("x", "x,y", "y", "y,").
+		AddSymbol("0").
+		SetInitial("x,y").
+		On("x,y", "0", "x").On("x", "0", "x").On("y", "0", "y").On("y,", "0", "y,").
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	other, err := NewBuilder().
+		AddState("z", "y,z").
+		AddSymbol("0").
+		SetInitial("z").
+		On("z", "0", "y,z").On("y,z", "0", "y,z").
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	// (x,y , z) then (x , y,z): both are named "(x,y,z)".
+	if _, err := Product([]*FiniteAutomaton{comma.(*FiniteAutomaton), other.(*FiniteAutomaton)}); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("Expected an ambiguous name error, got %v", err)
+	}
+}
@@ -0,0 +1,203 @@
+package fsm
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Registry of named automata loaded from a directory of definition files.
+// Each "<name>.json" file becomes the entry <name>. Readers never block:
+// the entries live in an immutable map that Load replaces atomically, and
+// each automaton is a Sealed snapshot, so a Run that started on the old
+// version finishes on it while new calls see the new one.
+
+// -----------------------------------------------------------------------------
+// Generic FSM API: Registry
+// -----------------------------------------------------------------------------
+
+// Entry is the state of one named automaton in a Registry.
+type Entry struct {
+	Name      string
+	Path      string
+	Automaton *Sealed   // last version that loaded successfully; nil if none has
+	Version   int       // 1 for the first successful load, then +1 per change
+	LoadedAt  time.Time // when Automaton was loaded
+	Err       error     // why the latest content of Path failed to load; nil if it loaded
+}
+
+// Registry maps names to validated automata and reloads them when their
+// definition files change. It is safe for concurrent use.
+type Registry struct {
+	dir    string
+	opts   []Option
+	logger *slog.Logger
+
+	mu      sync.Mutex                        // serialises Load
+	sums    map[string][sha256.Size]byte      // content last read per name; guarded by mu
+	entries atomic.Pointer[map[string]*Entry] // replaced, never modified, once published
+}
+
+// NewRegistry returns an empty registry for the definition files in dir.
+// Call Load, or Watch, to read them. opts are passed to Definition.Build for
+// every file; WithLogger also logs failed loads.
+func NewRegistry(dir string, opts ...Option) *Registry {
+	r := &Registry{
+		dir:    dir,
+		opts:   opts,
+		logger: applyOptions(opts).logger,
+		sums:   make(map[string][sha256.Size]byte),
+	}
+	r.entries.Store(&map[string]*Entry{})
+	return r
+}
+
+// Load reads the directory once: new and changed files are built and swapped
+// in, entries whose file is gone are dropped, and unchanged files are not
+// rebuilt. A file that fails to load keeps serving its previous version, with
+// Err set. Load returns the joined errors of every entry currently failing.
+func (r *Registry) Load() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	files, err := os.ReadDir(r.dir)
+	if err != nil {
+		return fmt.Errorf("FSM Registry Error: %w", err)
+	}
+
+	previous := *r.entries.Load()
+	next := make(map[string]*Entry, len(files))
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+		name := strings.TrimSuffix(file.Name(), ".json")
+		next[name] = r.load(name, filepath.Join(r.dir, file.Name()), previous[name])
+	}
+	for name := range r.sums {
+		if next[name] == nil {
+			delete(r.sums, name)
+		}
+	}
+	r.entries.Store(&next)
+
+	var errs []error
+	for _, entry := range r.Entries() {
+		if entry.Err != nil {
+			errs = append(errs, entry.Err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// load returns the entry for one file, reusing prev when the file is unchanged.
+func (r *Registry) load(name, path string, prev *Entry) *Entry {
+	entry := &Entry{Name: name, Path: path}
+	if prev != nil {
+		*entry = *prev
+	}
+
+	// 1. Skip files whose content has not changed since the last read.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		delete(r.sums, name) // rebuild once the file is readable again
+		entry.Err = fmt.Errorf("FSM Registry Error: %w", err)
+		return entry
+	}
+	sum := sha256.Sum256(data)
+	if last, ok := r.sums[name]; ok && last == sum && prev != nil {
+		return prev
+	}
+	r.sums[name] = sum
+
+	// 2. Parse, validate and freeze the new version.
+	def, err := ParseDefinition(data)
+	var automaton Automaton
+	if err == nil {
+		automaton, err = def.Build(r.opts...)
+	}
+	if err != nil {
+		entry.Err = fmt.Errorf("%s: %w", path, err)
+		if r.logger != nil {
+			r.logger.Warn("FSM registry load failed", "name", name, "version", entry.Version, "error", err)
+		}
+		return entry
+	}
+
+	entry.Automaton = automaton.(*FiniteAutomaton).Freeze()
+	entry.Version++
+	entry.LoadedAt = time.Now()
+	entry.Err = nil
+	return entry
+}
+
+// Watch polls the directory every interval, calling Load, until ctx is done.
+// It loads once immediately and returns ctx.Err(); load errors are reported
+// on the entries.
+func (r *Registry) Watch(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("FSM Registry Error: Watch interval must be positive, got %v", interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		_ = r.Load() // errors are kept on the entries
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Get returns the current version of the named automaton.
+func (r *Registry) Get(name string) (*Sealed, bool) {
+	entry := (*r.entries.Load())[name]
+	if entry == nil || entry.Automaton == nil {
+		return nil, false
+	}
+	return entry.Automaton, true
+}
+
+// Entry returns the state of the named entry.
+func (r *Registry) Entry(name string) (Entry, bool) {
+	entry := (*r.entries.Load())[name]
+	if entry == nil {
+		return Entry{}, false
+	}
+	return *entry, true
+}
+
+// Entries returns the state of every entry, sorted by name.
+func (r *Registry) Entries() []Entry {
+	entries := *r.entries.Load()
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	result := make([]Entry, len(names))
+	for i, name := range names {
+		result[i] = *entries[name]
+	}
+	return result
+}
+
+// Run runs input on the current version of the named automaton.
+func (r *Registry) Run(name, input string) (finalState string, err error) {
+	automaton, ok := r.Get(name)
+	if !ok {
+		return "", fmt.Errorf("FSM Registry Error: No automaton loaded as '%s'", name)
+	}
+	return automaton.Run(input)
+}
@@ -0,0 +1,183 @@
+package fsm
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// -----------------------------------------------------------------------------
+// UNIT TESTS FOR Registry
+// -----------------------------------------------------------------------------
+
+// parityJSON accepts inputs over {0,1} with an even number of the given symbol.
+func parityJSON(symbol string) string {
+	other := map[string]string{"0": "1", "1": "0"}[symbol]
+	return `{"states": ["Even", "Odd"], "alphabet": ["0", "1"], "initialState": "Even", "acceptingStates": ["Even"],
+	"transitions": {"Even": {"` + symbol + `": "Odd", "` + other + `": "Even"}, "Odd": {"` + symbol + `": "Even", "` + other + `": "Odd"}}}`
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+}
+
+func TestRegistry_Load(t *testing.T) {
+	dir := t.TempDir()
+	data, err := os.ReadFile(filepath.Join("testdata", "trap.json"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	writeFile(t, filepath.Join(dir, "trap.json"), string(data))
+	writeFile(t, filepath.Join(dir, "parity.json"), parityJSON("1"))
+	writeFile(t, filepath.Join(dir, "notes.txt"), "ignored")
+
+	r := NewRegistry(dir)
+	if _, ok := r.Get("parity"); ok {
+		t.Fatal("Registry should be empty before Load")
+	}
+	if err := r.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	entries := r.Entries()
+	if len(entries) != 2 || entries[0].Name != "parity" || entries[1].Name != "trap" {
+		t.Fatalf("Expected entries parity and trap, got %+v", entries)
+	}
+	if state, err := r.Run("trap", "ab"); err != nil || state != "Accept" {
+		t.Errorf("Run(trap, ab): got (%q, %v)", state, err)
+	}
+	if _, err := r.Run("missing", "ab"); err == nil || !strings.Contains(err.Error(), "No automaton loaded as 'missing'") {
+		t.Errorf("Expected an unknown name error, got %v", err)
+	}
+
+	// An unchanged file keeps its version.
+	if err := r.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if entry, _ := r.Entry("parity"); entry.Version != 1 || entry.Err != nil || entry.LoadedAt.IsZero() {
+		t.Errorf("Expected parity at version 1, got %+v", entry)
+	}
+
+	// A changed file is swapped in; a reader holding the old version keeps it.
+	old, _ := r.Get("parity")
+	writeFile(t, filepath.Join(dir, "parity.json"), parityJSON("0"))
+	if err := r.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	current, _ := r.Get("parity")
+	if entry, _ := r.Entry("parity"); entry.Version != 2 {
+		t.Errorf("Expected parity at version 2, got %d", entry.Version)
+	}
+	if state, _ := old.Run("1"); state != "Odd" {
+		t.Errorf("Old version Run(\"1\"): got %q, want Odd", state)
+	}
+	if state, _ := current.Run("1"); state != "Even" {
+		t.Errorf("New version Run(\"1\"): got %q, want Even", state)
+	}
+
+	// A broken file reports its error and keeps serving the last good version.
+	writeFile(t, filepath.Join(dir, "parity.json"), `{"states": ["A"], "initialState": "B"}`)
+	err = r.Load()
+	var issue Issue
+	if !errors.As(err, &issue) || issue.Kind != IssueUnknownInitial {
+		t.Errorf("Expected an unknown initial state issue, got %v", err)
+	}
+	entry, _ := r.Entry("parity")
+	if entry.Err == nil || entry.Version != 2 || entry.Automaton != current {
+		t.Errorf("Expected version 2 kept with an error, got %+v", entry)
+	}
+	writeFile(t, filepath.Join(dir, "parity.json"), `{"bogus": 1}`)
+	if err := r.Load(); err == nil || !strings.Contains(err.Error(), "parity.json") {
+		t.Errorf("Expected a definition error naming the file, got %v", err)
+	}
+
+	// Fixing the file clears the error; removing it drops the entry.
+	writeFile(t, filepath.Join(dir, "parity.json"), parityJSON("1"))
+	if err := r.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if entry, _ := r.Entry("parity"); entry.Err != nil || entry.Version != 3 {
+		t.Errorf("Expected parity at version 3, got %+v", entry)
+	}
+	if err := os.Remove(filepath.Join(dir, "trap.json")); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if err := r.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if _, ok := r.Entry("trap"); ok {
+		t.Error("Expected trap to be dropped")
+	}
+
+	// A missing directory is reported.
+	if err := NewRegistry(filepath.Join(dir, "nope")).Load(); err == nil {
+		t.Error("Expected an error for a missing directory")
+	}
+}
+
+func TestRegistry_Watch(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "parity.json")
+	writeFile(t, path, parityJSON("1"))
+
+	r := NewRegistry(dir)
+	if err := r.Watch(context.Background(), 0); err == nil {
+		t.Error("Expected an error for a zero interval")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- r.Watch(ctx, time.Millisecond) }()
+
+	// Readers run continuously while the file flips between two versions.
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for range 8 {
+		wg.Go(func() {
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				if state, err := r.Run("parity", "1"); err == nil && state != "Odd" && state != "Even" {
+					t.Errorf("Run(\"1\"): unexpected state %q", state)
+					return
+				}
+			}
+		})
+	}
+
+	waitForVersion := func(version int) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			if entry, ok := r.Entry("parity"); ok && entry.Version >= version {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("Timed out waiting for version %d", version)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	waitForVersion(1)
+	for v := 2; v <= 5; v++ {
+		writeFile(t, path, parityJSON([]string{"0", "1"}[v%2]))
+		waitForVersion(v)
+	}
+	close(stop)
+	wg.Wait()
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Watch: got %v, want context.Canceled", err)
+	}
+}
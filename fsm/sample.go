@@ -0,0 +1,122 @@
+package fsm
+
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+	"math/rand/v2"
+	"strings"
+)
+
+// Sampler draws inputs of a fixed length uniformly at random from the
+// language of an automaton, using the counted-paths table from count.go:
+// a random rank in [0, count) is drawn once and then unranked symbol by
+// symbol, so every matching input is equally likely.
+//
+// A Sampler is not safe for concurrent use because *rand.Rand is not.
+// It caches the tables of the last few lengths and targets it sampled; see
+// maxSampleTables.
+type Sampler struct {
+	fa       *FiniteAutomaton
+	rng      *rand.Rand
+	alphabet []string
+	tables   map[sampleKey][]map[string]*big.Int // cached completion counts
+}
+
+// maxSampleTables bounds the completion tables a Sampler keeps. Each one
+// holds a count per state and length up to its own, so the cache is cleared
+// rather than left to grow when many different lengths are sampled.
+const maxSampleTables = 16
+
+// sampleKey identifies a cached completion table: a length and a target
+// ("" targets the accepting states).
+type sampleKey struct {
+	length int
+	target string
+}
+
+// NewSampler returns a sampler over fa drawing randomness from rng.
+// Seed rng explicitly, e.g. rand.New(rand.NewPCG(1, 2)), for reproducible output.
+func NewSampler(fa *FiniteAutomaton, rng *rand.Rand) *Sampler {
+	return &Sampler{
+		fa:       fa,
+		rng:      rng,
+		alphabet: sortedKeys(fa.Alphabet),
+		tables:   make(map[sampleKey][]map[string]*big.Int),
+	}
+}
+
+// Sample returns a uniformly random accepted input of exactly n symbols.
+func (s *Sampler) Sample(n int) (string, error) {
+	return s.sample(n, sampleKey{length: n}, s.fa.IsAccepting)
+}
+
+// SampleEndingIn returns a uniformly random input of exactly n symbols ending in state.
+func (s *Sampler) SampleEndingIn(n int, state string) (string, error) {
+	if !s.fa.States[state] {
+		return "", fmt.Errorf("FSM Sample Error: State '%s' is not defined in the set of States (Q)", state)
+	}
+	return s.sample(n, sampleKey{length: n, target: state}, func(q string) bool { return q == state })
+}
+
+func (s *Sampler) sample(n int, key sampleKey, isTarget func(string) bool) (string, error) {
+	if n < 0 {
+		return "", fmt.Errorf("FSM Sample Error: Negative length %d", n)
+	}
+
+	counts, ok := s.tables[key]
+	if !ok {
+		if len(s.tables) >= maxSampleTables {
+			clear(s.tables)
+		}
+		counts = completionCounts(s.fa, s.alphabet, n, isTarget)
+		s.tables[key] = counts
+	}
+
+	total := counts[n][s.fa.InitialState]
+	if total == nil || total.Sign() == 0 {
+		return "", fmt.Errorf("FSM Sample Error: No matching input of length %d", n)
+	}
+
+	// Unrank: walk the symbols in order, skipping whole subtrees until the
+	// random rank falls inside one.
+	rank := randBelow(s.rng, total)
+	var sb strings.Builder
+	state := s.fa.InitialState
+	for remaining := n; remaining > 0; remaining-- {
+		for _, symbol := range s.alphabet {
+			next, ok := s.fa.Transitions[state][symbol]
+			if !ok || counts[remaining-1][next] == nil {
+				continue
+			}
+			subtree := counts[remaining-1][next]
+			if rank.Cmp(subtree) < 0 {
+				sb.WriteString(symbol)
+				state = next
+				break
+			}
+			rank.Sub(rank, subtree)
+		}
+	}
+	return sb.String(), nil
+}
+
+// randBelow returns a uniform random integer in [0, bound) by rejection
+// sampling over bound.BitLen() random bits. bound must be positive.
+func randBelow(rng *rand.Rand, bound *big.Int) *big.Int {
+	bitLen := bound.BitLen()
+	words := make([]big.Word, (bitLen+bits.UintSize-1)/bits.UintSize)
+	excess := uint(len(words)*bits.UintSize - bitLen)
+	candidate := new(big.Int)
+	for {
+		for i := range words {
+			words[i] = big.Word(rng.Uint64())
+		}
+		// Clear the bits above BitLen so each draw succeeds with probability > 1/2.
+		words[len(words)-1] &= ^big.Word(0) >> excess
+		candidate.SetBits(words)
+		if candidate.Cmp(bound) < 0 {
+			return new(big.Int).Set(candidate)
+		}
+	}
+}
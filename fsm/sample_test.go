@@ -0,0 +1,128 @@
+package fsm
+
+import (
+	"math/big"
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+// -----------------------------------------------------------------------------
+// UNIT TESTS FOR Sampler
+// -----------------------------------------------------------------------------
+
+func TestSampler_Reproducible(t *testing.T) {
+	fa := loadTrapFA(t)
+
+	first := NewSampler(fa, rand.New(rand.NewPCG(7, 11)))
+	second := NewSampler(fa, rand.New(rand.NewPCG(7, 11)))
+	for i := 0; i < 20; i++ {
+		a, errA := first.Sample(12)
+		b, errB := second.Sample(12)
+		if errA != nil || errB != nil {
+			t.Fatalf("Sample failed: %v / %v", errA, errB)
+		}
+		if a != b {
+			t.Fatalf("Samplers with the same seed diverged at draw %d: %q vs %q", i, a, b)
+		}
+	}
+}
+
+func TestSampler_MembershipAndUniformity(t *testing.T) {
+	fa := loadTrapFA(t)
+	sampler := NewSampler(fa, rand.New(rand.NewPCG(1, 2)))
+
+	// Length 4 has exactly four accepted inputs: aaab, abab, baab, bbab.
+	const draws = 4000
+	seen := make(map[string]int)
+	for i := 0; i < draws; i++ {
+		input, err := sampler.Sample(4)
+		if err != nil {
+			t.Fatalf("Sample(4) failed: %v", err)
+		}
+		if state, _ := fa.Run(input); !fa.IsAccepting(state) {
+			t.Fatalf("Sample(4) returned rejected input %q", input)
+		}
+		seen[input]++
+	}
+	if len(seen) != 4 {
+		t.Fatalf("Expected 4 distinct accepted inputs, got %v", seen)
+	}
+	for input, count := range seen {
+		if count < 800 || count > 1200 {
+			t.Errorf("Input %q drawn %d times out of %d; distribution looks non-uniform", input, count, draws)
+		}
+	}
+
+	// Long inputs exercise multi-word random ranks.
+	for i := 0; i < 10; i++ {
+		input, err := sampler.SampleEndingIn(300, "Trap")
+		if err != nil {
+			t.Fatalf("SampleEndingIn(300, Trap) failed: %v", err)
+		}
+		if state, _ := fa.Run(input); state != "Trap" || len(input) != 300 {
+			t.Errorf("SampleEndingIn(300, Trap) returned %q ending in %s", input, state)
+		}
+	}
+}
+
+func TestSampler_BoundedCache(t *testing.T) {
+	fa := loadTrapFA(t)
+	sampler := NewSampler(fa, rand.New(rand.NewPCG(1, 2)))
+
+	for n := 2; n < 2+3*maxSampleTables; n++ {
+		input, err := sampler.Sample(n)
+		if err != nil {
+			t.Fatalf("Sample(%d) failed: %v", n, err)
+		}
+		if state, _ := fa.Run(input); !fa.IsAccepting(state) {
+			t.Errorf("Sample(%d) returned rejected input %q", n, input)
+		}
+		if len(sampler.tables) > maxSampleTables {
+			t.Fatalf("Sampler keeps %d tables, want at most %d", len(sampler.tables), maxSampleTables)
+		}
+	}
+}
+
+func TestSampler_Errors(t *testing.T) {
+	fa := loadTrapFA(t)
+	sampler := NewSampler(fa, rand.New(rand.NewPCG(1, 2)))
+
+	tests := []struct {
+		name          string
+		sample        func() (string, error)
+		errorContains string
+	}{
+		{"NegativeLength", func() (string, error) { return sampler.Sample(-1) }, "Negative length"},
+		{"EmptyLanguage", func() (string, error) { return sampler.Sample(1) }, "No matching input of length 1"},
+		{"UnreachableState", func() (string, error) { return sampler.SampleEndingIn(3, "Orphan") }, "No matching input"},
+		{"UnknownState", func() (string, error) { return sampler.SampleEndingIn(3, "S99") }, "State 'S99' is not defined"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.sample()
+			if err == nil || !strings.Contains(err.Error(), tt.errorContains) {
+				t.Errorf("Expected error containing %q, got %v", tt.errorContains, err)
+			}
+		})
+	}
+}
+
+func TestRandBelow(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+	bounds := []*big.Int{
+		big.NewInt(1),
+		big.NewInt(2),
+		big.NewInt(1000),
+		new(big.Int).Lsh(big.NewInt(1), 64),
+		new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 200), big.NewInt(12345)),
+	}
+	for _, bound := range bounds {
+		for i := 0; i < 100; i++ {
+			if r := randBelow(rng, bound); r.Sign() < 0 || r.Cmp(bound) >= 0 {
+				t.Fatalf("randBelow(%s) returned out-of-range %s", bound, r)
+			}
+		}
+	}
+}
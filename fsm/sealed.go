@@ -0,0 +1,112 @@
+package fsm
+
+import (
+	"context"
+	"iter"
+	"maps"
+)
+
+// Immutable snapshots. A FiniteAutomaton exposes its 5-tuple as exported
+// maps, and NewFiniteAutomaton keeps the caller's transitions map as is, so
+// any holder of the automaton or of that map can change δ mid-Run. Freeze
+// copies everything into a Sealed value whose maps are never handed out.
+
+// -----------------------------------------------------------------------------
+// Generic FSM API Methods: Freeze
+// -----------------------------------------------------------------------------
+
+// Sealed is an immutable, validated automaton. Its methods behave like those
+// of the FiniteAutomaton it was frozen from, and all of them are safe for
+// concurrent use by multiple goroutines, provided the logger, tracer and
+// tokenizer it was built with are.
+type Sealed struct {
+	fa *FiniteAutomaton // private deep copy; never exposed or modified
+}
+
+var _ Automaton = (*Sealed)(nil)
+
+// Freeze returns an immutable snapshot of fa. Later changes to fa, or to the
+// maps it was built from, do not affect the snapshot.
+func (fa *FiniteAutomaton) Freeze() *Sealed {
+	return &Sealed{fa: fa.clone()}
+}
+
+// clone returns a deep copy of fa with the same settings.
+func (fa *FiniteAutomaton) clone() *FiniteAutomaton {
+	c := *fa
+	c.States = maps.Clone(fa.States)
+	c.Alphabet = maps.Clone(fa.Alphabet)
+	c.AcceptingStates = maps.Clone(fa.AcceptingStates)
+	c.Transitions = make(map[string]map[string]string, len(fa.Transitions))
+	for from, row := range fa.Transitions {
+		c.Transitions[from] = maps.Clone(row)
+	}
+	return &c
+}
+
+// Thaw returns a mutable deep copy of the snapshot, e.g. for Analyze or
+// Complete. Changing it does not affect s.
+func (s *Sealed) Thaw() *FiniteAutomaton {
+	return s.fa.clone()
+}
+
+// --- Read-only accessors ---
+
+// States returns Q in sorted order.
+func (s *Sealed) States() []string { return sortedKeys(s.fa.States) }
+
+// Alphabet returns Σ in sorted order.
+func (s *Sealed) Alphabet() []string { return sortedKeys(s.fa.Alphabet) }
+
+// InitialState returns q0.
+func (s *Sealed) InitialState() string { return s.fa.InitialState }
+
+// AcceptingStates returns F in sorted order.
+func (s *Sealed) AcceptingStates() []string { return sortedKeys(s.fa.AcceptingStates) }
+
+// Transition returns δ(from, symbol); ok is false when it is undefined.
+func (s *Sealed) Transition(from, symbol string) (to string, ok bool) {
+	to, ok = s.fa.Transitions[from][symbol]
+	return to, ok
+}
+
+// IsPartial reports whether the automaton was built with WithPartialTransitions.
+func (s *Sealed) IsPartial() bool { return s.fa.partial }
+
+// --- Execution, as for FiniteAutomaton ---
+
+func (s *Sealed) Run(input string) (finalState string, err error) { return s.fa.Run(input) }
+
+func (s *Sealed) RunContext(ctx context.Context, input string) (finalState string, err error) {
+	return s.fa.RunContext(ctx, input)
+}
+
+func (s *Sealed) Execute(input string) (RunResult, error) { return s.fa.Execute(input) }
+
+func (s *Sealed) ExecuteContext(ctx context.Context, input string) (RunResult, error) {
+	return s.fa.ExecuteContext(ctx, input)
+}
+
+func (s *Sealed) Walk(input string, visit func(state string)) (finalState string, err error) {
+	return s.fa.Walk(input, visit)
+}
+
+func (s *Sealed) RunSeq(symbols iter.Seq[string]) (finalState string, err error) {
+	return s.fa.RunSeq(symbols)
+}
+
+func (s *Sealed) RunBytes(input iter.Seq[byte]) (finalState string, err error) {
+	return s.fa.RunBytes(input)
+}
+
+func (s *Sealed) Steps(input string) iter.Seq2[int, Step] { return s.fa.Steps(input) }
+
+func (s *Sealed) StepsSeq(symbols iter.Seq[string]) iter.Seq2[int, Step] {
+	return s.fa.StepsSeq(symbols)
+}
+
+func (s *Sealed) IsAccepting(state string) bool { return s.fa.IsAccepting(state) }
+
+func (s *Sealed) ValidateInput(input string) bool { return s.fa.ValidateInput(input) }
+
+func (s *Sealed) Tokens(input string) iter.Seq2[int, string] { return s.fa.Tokens(input) }
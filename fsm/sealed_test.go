@@ -0,0 +1,133 @@
+package fsm
+
+import (
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// -----------------------------------------------------------------------------
+// UNIT TESTS FOR Freeze
+// -----------------------------------------------------------------------------
+
+func TestFreeze_IsolatedFromMutation(t *testing.T) {
+	transitions := map[string]map[string]string{
+		"S0": {"0": "S0", "1": "S1"},
+		"S1": {"0": "S2", "1": "S0"},
+		"S2": {"0": "S1", "1": "S2"},
+	}
+	automaton, err := NewFiniteAutomaton([]string{"S0", "S1", "S2"}, []string{"0", "1"}, "S0", []string{"S0", "S1", "S2"}, transitions)
+	if err != nil {
+		t.Fatalf("NewFiniteAutomaton failed: %v", err)
+	}
+	fa := automaton.(*FiniteAutomaton)
+	sealed := fa.Freeze()
+
+	// Vandalise the original and the map it was built from.
+	transitions["S0"]["1"] = "S2"
+	fa.AcceptingStates["S1"] = false
+	delete(fa.AcceptingStates, "S1")
+	fa.Alphabet["2"] = true
+	fa.InitialState = "S2"
+
+	if state, err := sealed.Run("1101"); err != nil || state != "S1" || !sealed.IsAccepting(state) {
+		t.Errorf("Run(\"1101\"): got (%q, %v), want accepting S1", state, err)
+	}
+	if sealed.ValidateInput("2") {
+		t.Error("ValidateInput(\"2\"): the snapshot's alphabet changed")
+	}
+
+	// Thaw hands out a copy; changing it leaves the snapshot alone.
+	thawed := sealed.Thaw()
+	thawed.Transitions["S0"]["0"] = "S2"
+	if to, ok := sealed.Transition("S0", "0"); !ok || to != "S0" {
+		t.Errorf("Transition(S0, 0): got (%q, %v), want S0", to, ok)
+	}
+	if _, ok := sealed.Transition("S0", "2"); ok {
+		t.Error("Transition(S0, 2) should be undefined")
+	}
+}
+
+func TestFreeze_Accessors(t *testing.T) {
+	fa := loadTrapFA(t)
+	sealed := fa.Freeze()
+
+	if !reflect.DeepEqual(sealed.States(), sortedKeys(fa.States)) ||
+		!reflect.DeepEqual(sealed.Alphabet(), sortedKeys(fa.Alphabet)) ||
+		!reflect.DeepEqual(sealed.AcceptingStates(), sortedKeys(fa.AcceptingStates)) ||
+		sealed.InitialState() != fa.InitialState || sealed.IsPartial() != fa.IsPartial() {
+		t.Errorf("Accessors do not match the frozen automaton %+v", fa)
+	}
+
+	// Every execution method matches the original.
+	for _, input := range []string{"", "ab", "aab", "abc", "x"} {
+		wantState, wantErr := fa.Run(input)
+		state, err := sealed.Run(input)
+		if state != wantState || !reflect.DeepEqual(err, wantErr) {
+			t.Errorf("Run(%q): got (%q, %v), want (%q, %v)", input, state, err, wantState, wantErr)
+		}
+		var visited []string
+		if state, _ := sealed.Walk(input, func(s string) { visited = append(visited, s) }); state != wantState || len(visited) == 0 {
+			t.Errorf("Walk(%q): got %q visiting %v", input, state, visited)
+		}
+		if sealed.ValidateInput(input) != fa.ValidateInput(input) {
+			t.Errorf("ValidateInput(%q) differs", input)
+		}
+		if got, want := slices.Collect(seqValues(sealed.Tokens(input))), slices.Collect(seqValues(fa.Tokens(input))); !reflect.DeepEqual(got, want) {
+			t.Errorf("Tokens(%q): got %v, want %v", input, got, want)
+		}
+		if got, want := len(slices.Collect(seqValues(sealed.Steps(input)))), len(slices.Collect(seqValues(fa.Steps(input)))); got != want {
+			t.Errorf("Steps(%q): got %d steps, want %d", input, got, want)
+		}
+	}
+	if state, err := sealed.RunSeq(slices.Values([]string{"a", "b"})); err != nil || state != "Accept" {
+		t.Errorf("RunSeq: got (%q, %v)", state, err)
+	}
+	if state, err := sealed.RunBytes(slices.Values([]byte("ab"))); err != nil || state != "Accept" {
+		t.Errorf("RunBytes: got (%q, %v)", state, err)
+	}
+	if n := len(slices.Collect(seqValues(sealed.StepsSeq(slices.Values([]string{"a", "b"}))))); n != 2 {
+		t.Errorf("StepsSeq: got %d steps, want 2", n)
+	}
+}
+
+// seqValues drops the keys of a Seq2.
+func seqValues[K, V any](seq func(yield func(K, V) bool)) func(yield func(V) bool) {
+	return func(yield func(V) bool) {
+		for _, v := range seq {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+func TestFreeze_ConcurrentRun(t *testing.T) {
+	fa := loadTrapFA(t)
+	sealed := fa.Freeze()
+	inputs := []string{"ab", "aab", "ba", "abab", "abc"}
+	expected := make(map[string]string, len(inputs))
+	for _, input := range inputs {
+		expected[input], _ = fa.Run(input)
+	}
+
+	// Run with -race: nothing reachable from these methods may write shared state.
+	var wg sync.WaitGroup
+	for g := range 16 {
+		wg.Go(func() {
+			for i := range 500 {
+				input := inputs[(g+i)%len(inputs)]
+				if state, err := sealed.Run(input); err != nil || state != expected[input] {
+					t.Errorf("Run(%q): got (%q, %v), want %q", input, state, err, expected[input])
+					return
+				}
+				sealed.IsAccepting(expected[input])
+				sealed.ValidateInput(input)
+				for range sealed.Steps(input) {
+				}
+			}
+		})
+	}
+	wg.Wait()
+}
@@ -0,0 +1,156 @@
+package fsm
+
+import (
+	"iter"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Tokenizer splits an input string into the symbols fed to the automaton.
+// Tokens yields each symbol with the byte offset where it starts; that offset
+// is the Position reported in RunError and in log records.
+//
+// Tokenizers never fail: text they cannot split is yielded as a symbol of its
+// own, which is then rejected because it is not in the alphabet.
+type Tokenizer interface {
+	Tokens(input string) iter.Seq2[int, string]
+}
+
+// TokenizerFunc adapts an ordinary function to the Tokenizer interface.
+type TokenizerFunc func(input string) iter.Seq2[int, string]
+
+// Tokens implements Tokenizer.
+func (f TokenizerFunc) Tokens(input string) iter.Seq2[int, string] { return f(input) }
+
+// -----------------------------------------------------------------------------
+// Built-in tokenizers
+// -----------------------------------------------------------------------------
+
+// RuneTokenizer yields one symbol per UTF-8 character. It is the default.
+func RuneTokenizer() Tokenizer {
+	return TokenizerFunc(func(input string) iter.Seq2[int, string] {
+		return func(yield func(int, string) bool) {
+			for pos, char := range input {
+				if !yield(pos, string(char)) {
+					return
+				}
+			}
+		}
+	})
+}
+
+// ByteTokenizer yields one symbol per byte, so invalid UTF-8 and multi-byte
+// characters are split into their individual bytes.
+func ByteTokenizer() Tokenizer {
+	return TokenizerFunc(func(input string) iter.Seq2[int, string] {
+		return func(yield func(int, string) bool) {
+			for pos := 0; pos < len(input); pos++ {
+				if !yield(pos, input[pos:pos+1]) {
+					return
+				}
+			}
+		}
+	})
+}
+
+// FixedWidthTokenizer yields consecutive chunks of width bytes. A shorter
+// trailing chunk is yielded as is. A width below 1 behaves like ByteTokenizer.
+func FixedWidthTokenizer(width int) Tokenizer {
+	width = max(width, 1)
+	return TokenizerFunc(func(input string) iter.Seq2[int, string] {
+		return func(yield func(int, string) bool) {
+			for pos := 0; pos < len(input); pos += width {
+				if !yield(pos, input[pos:min(pos+width, len(input))]) {
+					return
+				}
+			}
+		}
+	})
+}
+
+// LongestMatchTokenizer yields, at each offset, the longest of symbols that
+// prefixes the remaining input. Where no symbol matches, the next character
+// is yielded on its own. Empty symbols are ignored.
+func LongestMatchTokenizer(symbols []string) Tokenizer {
+	// Longest first, so the first match found is the longest one.
+	candidates := slices.DeleteFunc(slices.Clone(symbols), func(s string) bool { return s == "" })
+	slices.SortStableFunc(candidates, func(a, b string) int { return len(b) - len(a) })
+
+	return TokenizerFunc(func(input string) iter.Seq2[int, string] {
+		return func(yield func(int, string) bool) {
+			for pos := 0; pos < len(input); {
+				rest := input[pos:]
+				symbol := ""
+				for _, candidate := range candidates {
+					if strings.HasPrefix(rest, candidate) {
+						symbol = candidate
+						break
+					}
+				}
+				if symbol == "" {
+					_, size := utf8.DecodeRuneInString(rest)
+					symbol = rest[:size]
+				}
+				if !yield(pos, symbol) {
+					return
+				}
+				pos += len(symbol)
+			}
+		}
+	})
+}
+
+// DelimitedTokenizer yields the fields of the input separated by sep.
+// Empty fields (e.g. from doubled separators) are yielded as "" and are
+// therefore rejected unless "" is in the alphabet. An empty input has no
+// symbols. An empty sep behaves like WhitespaceTokenizer.
+func DelimitedTokenizer(sep string) Tokenizer {
+	if sep == "" {
+		return WhitespaceTokenizer()
+	}
+	return TokenizerFunc(func(input string) iter.Seq2[int, string] {
+		return func(yield func(int, string) bool) {
+			if input == "" {
+				return
+			}
+			pos := 0
+			for {
+				end := strings.Index(input[pos:], sep)
+				if end < 0 {
+					yield(pos, input[pos:])
+					return
+				}
+				if !yield(pos, input[pos:pos+end]) {
+					return
+				}
+				pos += end + len(sep)
+			}
+		}
+	})
+}
+
+// WhitespaceTokenizer yields the whitespace-separated words of the input;
+// runs of whitespace count as a single separator.
+func WhitespaceTokenizer() Tokenizer {
+	return TokenizerFunc(func(input string) iter.Seq2[int, string] {
+		return func(yield func(int, string) bool) {
+			start := -1
+			for pos, char := range input {
+				switch {
+				case !unicode.IsSpace(char) && start < 0:
+					start = pos
+				case unicode.IsSpace(char) && start >= 0:
+					if !yield(start, input[start:pos]) {
+						return
+					}
+					start = -1
+				}
+			}
+			if start >= 0 {
+				yield(start, input[start:])
+			}
+		}
+	})
+}
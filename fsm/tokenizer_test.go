@@ -0,0 +1,146 @@
+package fsm
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// -----------------------------------------------------------------------------
+// UNIT TESTS FOR Tokenizer
+// -----------------------------------------------------------------------------
+
+type token struct {
+	pos    int
+	symbol string
+}
+
+func collectTokens(t Tokenizer, input string) []token {
+	var out []token
+	for pos, symbol := range t.Tokens(input) {
+		out = append(out, token{pos, symbol})
+	}
+	return out
+}
+
+func TestTokenizers(t *testing.T) {
+	tests := []struct {
+		name      string
+		tokenizer Tokenizer
+		input     string
+		want      []token
+	}{
+		{"Rune", RuneTokenizer(), "aé1", []token{{0, "a"}, {1, "é"}, {3, "1"}}},
+		{"Rune empty", RuneTokenizer(), "", nil},
+		{"Byte", ByteTokenizer(), "aé", []token{{0, "a"}, {1, "\xc3"}, {2, "\xa9"}}},
+		{"Fixed width", FixedWidthTokenizer(2), "10110", []token{{0, "10"}, {2, "11"}, {4, "0"}}},
+		{"Fixed width below 1", FixedWidthTokenizer(0), "ab", []token{{0, "a"}, {1, "b"}}},
+		{"Longest match", LongestMatchTokenizer([]string{"1", "10", "<EOF>", ""}), "1101<EOF>", []token{{0, "1"}, {1, "10"}, {3, "1"}, {4, "<EOF>"}}},
+		{"Longest match unmatched", LongestMatchTokenizer([]string{"ab"}), "abéab", []token{{0, "ab"}, {2, "é"}, {4, "ab"}}},
+		{"Delimited", DelimitedTokenizer(","), "ab,c,,d", []token{{0, "ab"}, {3, "c"}, {5, ""}, {6, "d"}}},
+		{"Delimited trailing", DelimitedTokenizer(", "), "x, y, ", []token{{0, "x"}, {3, "y"}, {6, ""}}},
+		{"Delimited empty input", DelimitedTokenizer(","), "", nil},
+		{"Delimited empty separator", DelimitedTokenizer(""), " go  stop ", []token{{1, "go"}, {5, "stop"}}},
+		{"Whitespace", WhitespaceTokenizer(), "go\tstop\n go", []token{{0, "go"}, {3, "stop"}, {9, "go"}}},
+		{"Whitespace only", WhitespaceTokenizer(), " \t ", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := collectTokens(tt.tokenizer, tt.input)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Tokens(%q): got %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenizers_StopEarly(t *testing.T) {
+	tokenizers := map[string]Tokenizer{
+		"Rune":          RuneTokenizer(),
+		"Byte":          ByteTokenizer(),
+		"Fixed width":   FixedWidthTokenizer(1),
+		"Longest match": LongestMatchTokenizer([]string{"a"}),
+		"Delimited":     DelimitedTokenizer(" "),
+		"Whitespace":    WhitespaceTokenizer(),
+	}
+	for name, tokenizer := range tokenizers {
+		count := 0
+		for range tokenizer.Tokens("a a a") {
+			count++
+			break
+		}
+		if count != 1 {
+			t.Errorf("%s: expected the iterator to stop after one token, got %d", name, count)
+		}
+	}
+}
+
+// wordFA recognises "go" followed by any number of "go"/"stop" words, ending on "go".
+func wordFA(t *testing.T, opts ...Option) *FiniteAutomaton {
+	t.Helper()
+	transitions := map[string]map[string]string{
+		"Idle":   {"go": "Moving", "stop": "Idle"},
+		"Moving": {"go": "Moving", "stop": "Idle"},
+	}
+	a, err := NewFiniteAutomaton([]string{"Idle", "Moving"}, []string{"go", "stop"}, "Idle", []string{"Moving"}, transitions, opts...)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return a.(*FiniteAutomaton)
+}
+
+func TestRun_WithTokenizer(t *testing.T) {
+	fa := wordFA(t, WithTokenizer(WhitespaceTokenizer()))
+
+	state, err := fa.Run("go stop  go")
+	if err != nil || state != "Moving" {
+		t.Errorf("Run: got (%q, %v), want Moving", state, err)
+	}
+	if !fa.ValidateInput("stop go") || fa.ValidateInput("go jump") {
+		t.Error("ValidateInput does not use the tokenizer")
+	}
+
+	_, err = fa.Run("go jump")
+	var runErr *RunError
+	if !errors.As(err, &runErr) || runErr.Kind != KindInvalidSymbol || runErr.Symbol != "jump" || runErr.Position != 3 {
+		t.Errorf("Expected invalid symbol 'jump' at 3, got %v", err)
+	}
+
+	// Without a tokenizer every character is a symbol.
+	if _, err := wordFA(t).Run("go"); err == nil {
+		t.Error("Expected the default rune tokenizer to reject multi-character symbols")
+	}
+}
+
+func TestRun_WithLongestMatch(t *testing.T) {
+	transitions := map[string]map[string]string{
+		"Even": {"1": "Odd", "10": "Even", "<EOF>": "Done"},
+		"Odd":  {"1": "Even", "10": "Odd", "<EOF>": "Done"},
+		"Done": {"1": "Done", "10": "Done", "<EOF>": "Done"},
+	}
+	a, err := NewFiniteAutomaton([]string{"Even", "Odd", "Done"}, []string{"1", "10", "<EOF>"}, "Even", []string{"Done"},
+		transitions, WithLongestMatch())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	fa := a.(*FiniteAutomaton)
+
+	var visited []string
+	state, err := fa.Walk("1101<EOF>", func(s string) { visited = append(visited, s) })
+	if err != nil || state != "Done" {
+		t.Fatalf("Walk: got (%q, %v), want Done", state, err)
+	}
+	if want := []string{"Even", "Odd", "Odd", "Even", "Done"}; !slices.Equal(visited, want) {
+		t.Errorf("visited %v, want %v", visited, want)
+	}
+
+	// Tokenization survives Complete.
+	completed, err := fa.Complete("Sink")
+	if err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	if state, err := completed.Run("10<EOF>"); err != nil || state != "Done" {
+		t.Errorf("Completed Run: got (%q, %v), want Done", state, err)
+	}
+}
@@ -0,0 +1,300 @@
+// Package typed is a generic variant of the fsm engine in which states and
+// symbols are any comparable Go types rather than strings, so automata can
+// use ints, enums or structs directly. Adapter exposes a typed automaton
+// through the string-based fsm.Automaton interface.
+//
+// Configuration and run errors reuse the fsm types (fsm.Issue,
+// *fsm.RunError and *fsm.AbortError) with states and symbols formatted by fmt.Sprint, so
+// callers matching on them with errors.As work with either engine.
+package typed
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"slices"
+
+	"modulo_three_advanced/fsm"
+)
+
+// FiniteAutomaton is the 5-tuple (Q, Σ, q0, F, δ) over state type S and
+// symbol type A.
+type FiniteAutomaton[S comparable, A comparable] struct {
+	States          map[S]bool    // Q: Set of states
+	Alphabet        map[A]bool    // Σ: Input alphabet
+	InitialState    S             // q0: Initial state
+	AcceptingStates map[S]bool    // F: Set of accepting states
+	Transitions     map[S]map[A]S // δ: map[CurrentState]map[InputSymbol]NextState
+}
+
+// New validates the 5-tuple like fsm.NewFiniteAutomaton and returns the
+// configured engine. The first problem found is returned as an fsm.Issue.
+func New[S comparable, A comparable](
+	states []S,
+	alphabet []A,
+	initialState S,
+	acceptingStates []S,
+	transitions map[S]map[A]S,
+) (*FiniteAutomaton[S, A], error) {
+	fa := &FiniteAutomaton[S, A]{
+		States:          setOf(states),
+		Alphabet:        setOf(alphabet),
+		InitialState:    initialState,
+		AcceptingStates: setOf(acceptingStates),
+		Transitions:     transitions,
+	}
+
+	// 1. Validate Initial State is a member of Q
+	if !fa.States[initialState] {
+		return nil, fsm.Issue{Kind: fsm.IssueUnknownInitial, State: fmt.Sprint(initialState)}
+	}
+
+	// 2. Validate Accepting States are a subset of Q
+	for _, as := range acceptingStates {
+		if !fa.States[as] {
+			return nil, fsm.Issue{Kind: fsm.IssueUnknownAccepting, State: fmt.Sprint(as)}
+		}
+	}
+
+	// 3. Validate Transition Completeness (DFA property)
+	for _, current := range states {
+		row, ok := transitions[current]
+		if !ok {
+			return nil, fsm.Issue{Kind: fsm.IssueMissingRules, State: fmt.Sprint(current)}
+		}
+		for _, symbol := range alphabet {
+			next, ok := row[symbol]
+			if !ok {
+				return nil, fsm.Issue{Kind: fsm.IssueMissingTransition, State: fmt.Sprint(current), Symbol: fmt.Sprint(symbol)}
+			}
+			if !fa.States[next] {
+				return nil, fsm.Issue{Kind: fsm.IssueUndefinedTarget, State: fmt.Sprint(current), Symbol: fmt.Sprint(symbol), Target: fmt.Sprint(next)}
+			}
+		}
+	}
+	return fa, nil
+}
+
+func setOf[T comparable](items []T) map[T]bool {
+	set := make(map[T]bool, len(items))
+	for _, item := range items {
+		set[item] = true
+	}
+	return set
+}
+
+// -----------------------------------------------------------------------------
+// Generic FSM API Methods: Run, RunSeq
+// -----------------------------------------------------------------------------
+
+// Run processes the symbols in order and returns the final state.
+// A failure is reported as *fsm.RunError whose Position is the symbol index.
+func (fa *FiniteAutomaton[S, A]) Run(input []A) (S, error) {
+	return fa.RunSeq(slices.Values(input))
+}
+
+// RunSeq is Run over an iterator, so symbols can come from a channel, a
+// decoder or a generator without being collected into a slice first.
+func (fa *FiniteAutomaton[S, A]) RunSeq(input iter.Seq[A]) (S, error) {
+	return fa.RunSeqContext(context.Background(), input)
+}
+
+// RunContext is Run that stops with an *fsm.AbortError of kind
+// fsm.KindCanceled once ctx is done. The context is checked every few
+// thousand symbols.
+func (fa *FiniteAutomaton[S, A]) RunContext(ctx context.Context, input []A) (S, error) {
+	return fa.RunSeqContext(ctx, slices.Values(input))
+}
+
+// checkInterval is the number of steps between checks of the run's context.
+const checkInterval = 4096
+
+// RunSeqContext is RunSeq that stops like RunContext once ctx is done, so
+// long lazily produced inputs can be canceled without being collected first.
+func (fa *FiniteAutomaton[S, A]) RunSeqContext(ctx context.Context, input iter.Seq[A]) (S, error) {
+	result, err := fa.ExecuteSeqContext(ctx, input)
+	if err != nil {
+		var zero S
+		return zero, err
+	}
+	return result.FinalState, nil
+}
+
+// -----------------------------------------------------------------------------
+// Generic FSM API Method: ExecuteSeqContext
+// -----------------------------------------------------------------------------
+
+// RunResult is fsm.RunResult for typed states, without the visit counts.
+type RunResult[S comparable] struct {
+	FinalState S    // state reached; on failure, the state the run stopped in
+	Accepted   bool // the whole input was consumed and FinalState is accepting
+	Consumed   int  // symbols consumed, i.e. transitions taken
+	FailedAt   int  // index of the symbol the run stopped at; -1 if it did not fail
+}
+
+// ExecuteSeqContext runs input like RunSeqContext and reports the whole run.
+// On failure the error is the one RunSeqContext returns, and the result
+// describes the run up to that point.
+func (fa *FiniteAutomaton[S, A]) ExecuteSeqContext(ctx context.Context, input iter.Seq[A]) (RunResult[S], error) {
+	result := RunResult[S]{FinalState: fa.InitialState, FailedAt: -1}
+	done := ctx.Done() // nil for context.Background(), which is never checked
+	for symbol := range input {
+		pos := result.Consumed
+		if done != nil && pos&(checkInterval-1) == 0 {
+			select {
+			case <-done:
+				result.FailedAt = pos
+				return result, &fsm.AbortError{Kind: fsm.KindCanceled, State: fmt.Sprint(result.FinalState), Steps: pos, Position: pos, Err: ctx.Err()}
+			default:
+			}
+		}
+		next, kind, ok := fa.step(result.FinalState, symbol)
+		if !ok {
+			result.FailedAt = pos
+			return result, &fsm.RunError{Kind: kind, State: fmt.Sprint(result.FinalState), Symbol: fmt.Sprint(symbol), Position: pos}
+		}
+		result.FinalState = next
+		result.Consumed++
+	}
+	result.Accepted = fa.IsAccepting(result.FinalState)
+	return result, nil
+}
+
+// step applies δ once, reporting why it failed when there is no transition.
+func (fa *FiniteAutomaton[S, A]) step(current S, symbol A) (S, fsm.ErrorKind, bool) {
+	row, ok := fa.Transitions[current]
+	if !ok {
+		return current, fsm.KindMissingRules, false
+	}
+	next, ok := row[symbol]
+	if !ok {
+		return current, fsm.KindInvalidSymbol, false
+	}
+	return next, "", true
+}
+
+// IsAccepting reports whether state is in F.
+func (fa *FiniteAutomaton[S, A]) IsAccepting(state S) bool {
+	return fa.AcceptingStates[state]
+}
+
+// ValidateInput reports whether every symbol of input is in Σ.
+func (fa *FiniteAutomaton[S, A]) ValidateInput(input []A) bool {
+	for _, symbol := range input {
+		if !fa.Alphabet[symbol] {
+			return false
+		}
+	}
+	return true
+}
+
+// -----------------------------------------------------------------------------
+// Adapter: the string-based fsm.Automaton view
+// -----------------------------------------------------------------------------
+
+// Adapter satisfies fsm.Automaton for a typed automaton. Input strings are
+// split by Tokenizer, each symbol is converted with the parse function, and
+// states are reported by their format name.
+type Adapter[S comparable, A comparable] struct {
+	Tokenizer fsm.Tokenizer // splits input into symbols; fsm.RuneTokenizer when nil
+
+	fa     *FiniteAutomaton[S, A]
+	parse  func(symbol string) (A, bool)
+	format func(state S) string
+	names  map[string]S // format name -> state, for IsAccepting
+}
+
+// Adapt wraps fa. parse converts a symbol string and reports false for
+// symbols with no typed counterpart; format names a state. Names must be
+// unique across the states of fa.
+func Adapt[S comparable, A comparable](fa *FiniteAutomaton[S, A], parse func(symbol string) (A, bool), format func(state S) string) *Adapter[S, A] {
+	names := make(map[string]S, len(fa.States))
+	for state := range fa.States {
+		names[format(state)] = state
+	}
+	return &Adapter[S, A]{fa: fa, parse: parse, format: format, names: names}
+}
+
+// Strings wraps a string-specialised automaton, converting nothing.
+func Strings(fa *FiniteAutomaton[string, string]) *Adapter[string, string] {
+	identity := func(s string) string { return s }
+	return Adapt(fa, func(symbol string) (string, bool) { return symbol, true }, identity)
+}
+
+var _ fsm.Automaton = (*Adapter[string, string])(nil)
+
+// Automaton returns the wrapped typed automaton.
+func (a *Adapter[S, A]) Automaton() *FiniteAutomaton[S, A] {
+	return a.fa
+}
+
+// Run implements fsm.Automaton. Errors carry the byte offset of the
+// offending symbol, as with fsm.FiniteAutomaton.
+func (a *Adapter[S, A]) Run(input string) (string, error) {
+	result, err := a.Execute(input)
+	if err != nil {
+		return "", err
+	}
+	return result.FinalState, nil
+}
+
+// Execute implements fsm.Automaton.
+func (a *Adapter[S, A]) Execute(input string) (fsm.RunResult, error) {
+	current := a.fa.InitialState
+	visits := map[S]int{current: 1}
+	result := fsm.RunResult{FailedAt: -1}
+	var runErr *fsm.RunError
+	for pos, token := range a.tokenizer().Tokens(input) {
+		symbol, ok := a.parse(token)
+		if !ok {
+			runErr = &fsm.RunError{Kind: fsm.KindInvalidSymbol, State: a.format(current), Symbol: token, Position: pos}
+			break
+		}
+		next, kind, ok := a.fa.step(current, symbol)
+		if !ok {
+			runErr = &fsm.RunError{Kind: kind, State: a.format(current), Symbol: token, Position: pos}
+			break
+		}
+		current = next
+		visits[current]++
+		result.Consumed++
+	}
+
+	result.FinalState = a.format(current)
+	result.Visits = make(map[string]int, len(visits))
+	for state, n := range visits {
+		result.Visits[a.format(state)] = n
+	}
+	if runErr != nil {
+		result.FailedAt = runErr.Position
+		return result, runErr
+	}
+	result.Accepted = a.fa.IsAccepting(current)
+	return result, nil
+}
+
+// IsAccepting implements fsm.Automaton.
+func (a *Adapter[S, A]) IsAccepting(state string) bool {
+	typedState, ok := a.names[state]
+	return ok && a.fa.IsAccepting(typedState)
+}
+
+// ValidateInput implements fsm.Automaton.
+func (a *Adapter[S, A]) ValidateInput(input string) bool {
+	for _, token := range a.tokenizer().Tokens(input) {
+		symbol, ok := a.parse(token)
+		if !ok || !a.fa.Alphabet[symbol] {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *Adapter[S, A]) tokenizer() fsm.Tokenizer {
+	if a.Tokenizer == nil {
+		return defaultTokenizer
+	}
+	return a.Tokenizer
+}
+
+var defaultTokenizer = fsm.RuneTokenizer()
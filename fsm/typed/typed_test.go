@@ -0,0 +1,232 @@
+package typed
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+
+	"modulo_three_advanced/fsm"
+)
+
+// -----------------------------------------------------------------------------
+// UNIT TESTS FOR the typed engine
+// -----------------------------------------------------------------------------
+
+type light int
+
+const (
+	red light = iota
+	green
+	yellow
+)
+
+type signal byte
+
+// trafficLight cycles red -> green -> yellow -> red on 't' and stays put on 'w'.
+func trafficLight(t *testing.T) *FiniteAutomaton[light, signal] {
+	t.Helper()
+	fa, err := New(
+		[]light{red, green, yellow},
+		[]signal{'t', 'w'},
+		red,
+		[]light{red},
+		map[light]map[signal]light{
+			red:    {'t': green, 'w': red},
+			green:  {'t': yellow, 'w': green},
+			yellow: {'t': red, 'w': yellow},
+		},
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return fa
+}
+
+func TestRun(t *testing.T) {
+	fa := trafficLight(t)
+
+	tests := []struct {
+		input    []signal
+		expected light
+	}{
+		{nil, red},
+		{[]signal{'t'}, green},
+		{[]signal{'t', 'w', 't'}, yellow},
+		{[]signal{'t', 't', 't'}, red},
+	}
+	for _, tt := range tests {
+		state, err := fa.Run(tt.input)
+		if err != nil || state != tt.expected {
+			t.Errorf("Run(%q): got (%v, %v), want %v", tt.input, state, err, tt.expected)
+		}
+	}
+
+	_, err := fa.Run([]signal{'t', 'x'})
+	var runErr *fsm.RunError
+	if !errors.As(err, &runErr) || runErr.Kind != fsm.KindInvalidSymbol || runErr.State != "1" || runErr.Symbol != "120" || runErr.Position != 1 {
+		t.Errorf("Expected invalid symbol at index 1, got %v", err)
+	}
+}
+
+func TestRunSeq(t *testing.T) {
+	fa := trafficLight(t)
+
+	ch := make(chan signal, 2)
+	ch <- 't'
+	ch <- 't'
+	close(ch)
+	state, err := fa.RunSeq(func(yield func(signal) bool) {
+		for s := range ch {
+			if !yield(s) {
+				return
+			}
+		}
+	})
+	if err != nil || state != yellow {
+		t.Errorf("RunSeq: got (%v, %v), want yellow", state, err)
+	}
+}
+
+func TestRunContext(t *testing.T) {
+	fa := trafficLight(t)
+	if state, err := fa.RunContext(context.Background(), []signal{'t', 't'}); err != nil || state != yellow {
+		t.Errorf("RunContext: got (%v, %v), want yellow", state, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := fa.RunContext(ctx, []signal{'t'})
+	var abortErr *fsm.AbortError
+	if !errors.As(err, &abortErr) || abortErr.Kind != fsm.KindCanceled || abortErr.State != "0" || !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a canceled run in state 0, got %v", err)
+	}
+	if _, err := fa.RunSeqContext(ctx, slices.Values([]signal{'t'})); !errors.Is(err, context.Canceled) {
+		t.Errorf("RunSeqContext: expected a canceled run, got %v", err)
+	}
+}
+
+func TestExecuteSeqContext(t *testing.T) {
+	fa := trafficLight(t)
+
+	tests := []struct {
+		name     string
+		input    []signal
+		expected RunResult[light]
+		fails    bool
+	}{
+		{"Accepted", []signal{'t', 't', 't'}, RunResult[light]{FinalState: red, Accepted: true, Consumed: 3, FailedAt: -1}, false},
+		{"Not accepting", []signal{'t', 'w'}, RunResult[light]{FinalState: green, Consumed: 2, FailedAt: -1}, false},
+		{"Invalid symbol", []signal{'t', 'x', 't'}, RunResult[light]{FinalState: green, Consumed: 1, FailedAt: 1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := fa.ExecuteSeqContext(context.Background(), slices.Values(tt.input))
+			if (err != nil) != tt.fails || result != tt.expected {
+				t.Errorf("got (%+v, %v), want %+v", result, err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsAcceptingAndValidateInput(t *testing.T) {
+	fa := trafficLight(t)
+	if !fa.IsAccepting(red) || fa.IsAccepting(green) {
+		t.Error("IsAccepting mismatch")
+	}
+	if !fa.ValidateInput([]signal{'t', 'w'}) || fa.ValidateInput([]signal{'x'}) {
+		t.Error("ValidateInput mismatch")
+	}
+}
+
+func TestNew_ConfigErrors(t *testing.T) {
+	complete := map[int]map[bool]int{0: {false: 0, true: 1}, 1: {false: 1, true: 0}}
+	tests := []struct {
+		name        string
+		initial     int
+		accepting   []int
+		transitions map[int]map[bool]int
+		want        fsm.Issue
+	}{
+		{"Unknown initial", 7, nil, complete, fsm.Issue{Kind: fsm.IssueUnknownInitial, State: "7"}},
+		{"Unknown accepting", 0, []int{9}, complete, fsm.Issue{Kind: fsm.IssueUnknownAccepting, State: "9"}},
+		{"Missing rules", 0, nil, map[int]map[bool]int{0: complete[0]}, fsm.Issue{Kind: fsm.IssueMissingRules, State: "1"}},
+		{"Missing transition", 0, nil, map[int]map[bool]int{0: complete[0], 1: {false: 1}},
+			fsm.Issue{Kind: fsm.IssueMissingTransition, State: "1", Symbol: "true"}},
+		{"Undefined target", 0, nil, map[int]map[bool]int{0: {false: 0, true: 5}, 1: complete[1]},
+			fsm.Issue{Kind: fsm.IssueUndefinedTarget, State: "0", Symbol: "true", Target: "5"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New([]int{0, 1}, []bool{false, true}, tt.initial, tt.accepting, tt.transitions)
+			var issue fsm.Issue
+			if !errors.As(err, &issue) || issue != tt.want {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunSeq_MissingRules(t *testing.T) {
+	fa := trafficLight(t)
+	delete(fa.Transitions, green)
+	_, err := fa.Run([]signal{'t', 't'})
+	var runErr *fsm.RunError
+	if !errors.As(err, &runErr) || runErr.Kind != fsm.KindMissingRules {
+		t.Errorf("Expected missing rules, got %v", err)
+	}
+}
+
+func TestStrings(t *testing.T) {
+	fa, err := New(
+		[]string{"Idle", "Moving"},
+		[]string{"go", "stop"},
+		"Idle",
+		[]string{"Moving"},
+		map[string]map[string]string{
+			"Idle":   {"go": "Moving", "stop": "Idle"},
+			"Moving": {"go": "Moving", "stop": "Idle"},
+		},
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	adapter := Strings(fa)
+	adapter.Tokenizer = fsm.WhitespaceTokenizer()
+
+	var automaton fsm.Automaton = adapter
+	if state, err := automaton.Run("stop go"); err != nil || state != "Moving" || !automaton.IsAccepting(state) {
+		t.Errorf("Run: got (%q, %v), want accepting Moving", state, err)
+	}
+	if automaton.IsAccepting("Parked") {
+		t.Error("Unknown states are not accepting")
+	}
+	if !automaton.ValidateInput("go go") || automaton.ValidateInput("go jump") {
+		t.Error("ValidateInput mismatch")
+	}
+	if adapter.Automaton() != fa {
+		t.Error("Automaton() should return the wrapped automaton")
+	}
+
+	result, err := automaton.Execute("go stop go")
+	expected := fsm.RunResult{FinalState: "Moving", Accepted: true, Consumed: 3, FailedAt: -1, Visits: map[string]int{"Idle": 2, "Moving": 2}}
+	if err != nil || !reflect.DeepEqual(result, expected) {
+		t.Errorf("Execute: got (%+v, %v), want %+v", result, err, expected)
+	}
+	if result, err := automaton.Execute("go jump"); err == nil || result.FailedAt != 3 || result.Consumed != 1 || result.Accepted {
+		t.Errorf("Execute(\"go jump\"): got (%+v, %v), want a failure at 3", result, err)
+	}
+
+	_, err = automaton.Run("go jump")
+	var runErr *fsm.RunError
+	if !errors.As(err, &runErr) || runErr.Symbol != "jump" || runErr.State != "Moving" || runErr.Position != 3 {
+		t.Errorf("Expected invalid symbol 'jump' at 3, got %v", err)
+	}
+
+	// A symbol the parser accepts but the alphabet lacks fails in δ.
+	adapter.Tokenizer = nil
+	if _, err := automaton.Run("g"); !errors.As(err, &runErr) || runErr.Kind != fsm.KindInvalidSymbol {
+		t.Errorf("Expected invalid symbol, got %v", err)
+	}
+}
@@ -0,0 +1,214 @@
+package fsm
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// IssueKind classifies a configuration problem found by NewFiniteAutomaton
+// or Builder.Build.
+type IssueKind string
+
+const (
+	IssueUnknownInitial    IssueKind = "unknown_initial_state"
+	IssueUnknownAccepting  IssueKind = "unknown_accepting_state"
+	IssueMissingRules      IssueKind = "missing_rules"
+	IssueMissingTransition IssueKind = "missing_transition"
+	IssueUndefinedTarget   IssueKind = "undefined_target"
+	IssueUndeclaredSource  IssueKind = "undeclared_source"
+	IssueUnknownSymbol     IssueKind = "unknown_symbol"
+
+	// Found by Builder only.
+	IssueEmptyState            IssueKind = "empty_state"
+	IssueEmptySymbol           IssueKind = "empty_symbol"
+	IssueDuplicateState        IssueKind = "duplicate_state"
+	IssueDuplicateSymbol       IssueKind = "duplicate_symbol"
+	IssueConflictingInitial    IssueKind = "conflicting_initial_state"
+	IssueConflictingTransition IssueKind = "conflicting_transition"
+)
+
+// Issue is a single configuration problem. It implements error, and its
+// message is the one NewFiniteAutomaton has always returned for the problem.
+type Issue struct {
+	Kind   IssueKind
+	State  string // state the issue is about (the source state for transitions)
+	Symbol string // symbol involved, if any
+	Target string // transition target, for IssueUndefinedTarget and IssueConflictingTransition
+	Other  string // the second, conflicting value, for IssueConflictingInitial and IssueConflictingTransition
+}
+
+func (i Issue) Error() string {
+	switch i.Kind {
+	case IssueUnknownInitial:
+		if i.State == "" {
+			return "FSM Config Error: Initial state not set"
+		}
+		return fmt.Sprintf("FSM Config Error: Initial state '%s' is not defined in the set of States (Q)", i.State)
+	case IssueUnknownAccepting:
+		return fmt.Sprintf("FSM Config Error: Accepting state '%s' is not defined in the set of States (Q)", i.State)
+	case IssueMissingRules:
+		return fmt.Sprintf("FSM Config Error: Missing transition rules for state '%s' (not in δ)", i.State)
+	case IssueMissingTransition:
+		return fmt.Sprintf("FSM Config Error: Missing transition for state '%s' on symbol '%s'", i.State, i.Symbol)
+	case IssueUndefinedTarget:
+		return fmt.Sprintf("FSM Config Error: Transition from '%s' on '%s' leads to undefined state '%s'", i.State, i.Symbol, i.Target)
+	case IssueUndeclaredSource:
+		return fmt.Sprintf("FSM Config Error: Transition rules defined for undeclared state '%s'", i.State)
+	case IssueEmptyState:
+		return "FSM Config Error: State name must not be empty"
+	case IssueEmptySymbol:
+		return "FSM Config Error: Symbol must not be empty"
+	case IssueDuplicateState:
+		return fmt.Sprintf("FSM Config Error: State '%s' declared more than once", i.State)
+	case IssueDuplicateSymbol:
+		return fmt.Sprintf("FSM Config Error: Symbol '%s' declared more than once", i.Symbol)
+	case IssueConflictingInitial:
+		return fmt.Sprintf("FSM Config Error: Initial state set to both '%s' and '%s'", i.State, i.Other)
+	case IssueConflictingTransition:
+		return fmt.Sprintf("FSM Config Error: Transition from '%s' on '%s' defined as both '%s' and '%s'", i.State, i.Symbol, i.Target, i.Other)
+	default:
+		return fmt.Sprintf("FSM Config Error: Transition from '%s' uses symbol '%s' which is not in the alphabet (Σ)", i.State, i.Symbol)
+	}
+}
+
+// ValidationError is returned when NewFiniteAutomaton runs with WithAllErrors,
+// and by Builder.Build.
+// It carries every issue found; Unwrap exposes them so errors.As can match
+// an individual Issue.
+type ValidationError struct {
+	Issues []Issue
+}
+
+func (v *ValidationError) Error() string {
+	messages := make([]string, len(v.Issues))
+	for i, issue := range v.Issues {
+		messages[i] = issue.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Unwrap returns the individual issues, like an errors.Join result.
+func (v *ValidationError) Unwrap() []error {
+	errs := make([]error, len(v.Issues))
+	for i, issue := range v.Issues {
+		errs[i] = issue
+	}
+	return errs
+}
+
+// Report renders the issues as a numbered, human-readable list.
+func (v *ValidationError) Report() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d configuration issue(s):\n", len(v.Issues))
+	for i, issue := range v.Issues {
+		fmt.Fprintf(&sb, "  %d. [%s] %s\n", i+1, issue.Kind, strings.TrimPrefix(issue.Error(), "FSM Config Error: "))
+	}
+	return sb.String()
+}
+
+// collectIssues checks the 5-tuple. Unless all is set it stops at the first
+// problem, exactly like the original validation; with all set it keeps going
+// and also runs the extended checks for undeclared source states and symbols
+// outside Σ, including where their transitions lead. With partial set, missing transitions are allowed but defined
+// ones must still lead to declared states.
+func collectIssues(
+	states []string,
+	alphabet []string,
+	initialState string,
+	acceptingStates []string,
+	transitions map[string]map[string]string,
+	stateSet map[string]bool,
+	alphaSet map[string]bool,
+	partial bool,
+	all bool,
+	event func(step string),
+) []Issue {
+	var issues []Issue
+	report := func(issue Issue) bool {
+		issues = append(issues, issue)
+		return !all
+	}
+
+	// 1. Validate Initial State is a member of Q
+	event("validate.initial_state")
+	if _, ok := stateSet[initialState]; !ok {
+		if report(Issue{Kind: IssueUnknownInitial, State: initialState}) {
+			return issues
+		}
+	}
+
+	// 2. Validate Accepting States are a subset of Q
+	event("validate.accepting_states")
+	for _, as := range acceptingStates {
+		if _, ok := stateSet[as]; !ok {
+			if report(Issue{Kind: IssueUnknownAccepting, State: as}) {
+				return issues
+			}
+		}
+	}
+
+	// 3. Validate Transition Completeness (DFA property)
+	// Check that every state on every alphabet symbol has a valid transition defined and leads to a valid state.
+	event("validate.transitions")
+	for _, currentState := range states {
+		transitionsFromCurrent, ok := transitions[currentState]
+		if !ok && !partial {
+			if report(Issue{Kind: IssueMissingRules, State: currentState}) {
+				return issues
+			}
+			continue
+		}
+
+		for _, symbol := range alphabet {
+			nextState, ok := transitionsFromCurrent[symbol]
+			if !ok {
+				if !partial && report(Issue{Kind: IssueMissingTransition, State: currentState, Symbol: symbol}) {
+					return issues
+				}
+				continue
+			}
+			// Check that the resulting nextState is also a member of Q
+			if _, ok := stateSet[nextState]; !ok {
+				if report(Issue{Kind: IssueUndefinedTarget, State: currentState, Symbol: symbol, Target: nextState}) {
+					return issues
+				}
+			}
+		}
+	}
+
+	// 4. Extended checks: rows for undeclared states and symbols outside Σ.
+	if !all {
+		return issues
+	}
+	for _, from := range slices.Sorted(maps.Keys(transitions)) {
+		if !stateSet[from] {
+			issues = append(issues, Issue{Kind: IssueUndeclaredSource, State: from})
+			continue
+		}
+		for _, symbol := range slices.Sorted(maps.Keys(transitions[from])) {
+			if alphaSet[symbol] {
+				continue
+			}
+			issues = append(issues, Issue{Kind: IssueUnknownSymbol, State: from, Symbol: symbol})
+			if to := transitions[from][symbol]; !stateSet[to] {
+				issues = append(issues, Issue{Kind: IssueUndefinedTarget, State: from, Symbol: symbol, Target: to})
+			}
+		}
+	}
+	return issues
+}
+
+// issuesToError converts collected issues into the error NewFiniteAutomaton
+// returns: a *ValidationError when all is set, otherwise the single issue.
+func issuesToError(issues []Issue, all bool) error {
+	switch {
+	case len(issues) == 0:
+		return nil
+	case all:
+		return &ValidationError{Issues: issues}
+	default:
+		return issues[0]
+	}
+}
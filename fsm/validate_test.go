@@ -0,0 +1,155 @@
+package fsm
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// -----------------------------------------------------------------------------
+// UNIT TESTS FOR WithAllErrors / ValidationError
+// -----------------------------------------------------------------------------
+
+func brokenConfig() ([]string, []string, string, []string, map[string]map[string]string) {
+	states := []string{"A", "B"}
+	alphabet := []string{"0", "1"}
+	transitions := map[string]map[string]string{
+		"A": {"0": "B", "1": "X", "2": "A"},
+		"C": {"0": "A"},
+	}
+	return states, alphabet, "Z", []string{"Q"}, transitions
+}
+
+func TestValidate_DefaultReturnsFirstIssue(t *testing.T) {
+	states, alphabet, initial, accepting, transitions := brokenConfig()
+	_, err := NewFiniteAutomaton(states, alphabet, initial, accepting, transitions)
+
+	want := "FSM Config Error: Initial state 'Z' is not defined in the set of States (Q)"
+	if err == nil || err.Error() != want {
+		t.Fatalf("Expected %q, got %v", want, err)
+	}
+	var issue Issue
+	if !errors.As(err, &issue) || issue.Kind != IssueUnknownInitial || issue.State != "Z" {
+		t.Errorf("Expected an IssueUnknownInitial, got %+v", issue)
+	}
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		t.Error("Default mode should not return a ValidationError")
+	}
+}
+
+func TestValidate_AllErrors(t *testing.T) {
+	states, alphabet, initial, accepting, transitions := brokenConfig()
+	_, err := NewFiniteAutomaton(states, alphabet, initial, accepting, transitions, WithAllErrors())
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected a ValidationError, got %v", err)
+	}
+	want := []Issue{
+		{Kind: IssueUnknownInitial, State: "Z"},
+		{Kind: IssueUnknownAccepting, State: "Q"},
+		{Kind: IssueUndefinedTarget, State: "A", Symbol: "1", Target: "X"},
+		{Kind: IssueMissingRules, State: "B"},
+		{Kind: IssueUnknownSymbol, State: "A", Symbol: "2"},
+		{Kind: IssueUndeclaredSource, State: "C"},
+	}
+	if len(validationErr.Issues) != len(want) {
+		t.Fatalf("Expected %d issues, got %d: %v", len(want), len(validationErr.Issues), validationErr.Issues)
+	}
+	for i, issue := range validationErr.Issues {
+		if issue != want[i] {
+			t.Errorf("Issue %d: got %+v, want %+v", i, issue, want[i])
+		}
+	}
+
+	// Every issue is reachable through errors.Is and appears in the message.
+	for _, issue := range want {
+		if !errors.Is(err, issue) {
+			t.Errorf("errors.Is did not match %+v", issue)
+		}
+		if !strings.Contains(err.Error(), issue.Error()) {
+			t.Errorf("Error() is missing %q", issue.Error())
+		}
+	}
+
+	report := validationErr.Report()
+	if !strings.HasPrefix(report, "6 configuration issue(s):\n") ||
+		!strings.Contains(report, "  6. [undeclared_source] Transition rules defined for undeclared state 'C'\n") {
+		t.Errorf("Unexpected report:\n%s", report)
+	}
+}
+
+func TestValidate_AllErrorsMissingTransitionsAndPartial(t *testing.T) {
+	states := []string{"A", "B"}
+	alphabet := []string{"0", "1"}
+	transitions := map[string]map[string]string{"A": {"0": "Y"}}
+
+	_, err := NewFiniteAutomaton(states, alphabet, "A", nil, transitions, WithAllErrors())
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Issues) != 3 {
+		t.Fatalf("Expected 3 issues, got %v", err)
+	}
+	if got := validationErr.Issues[1]; got.Kind != IssueMissingTransition || got.Symbol != "1" {
+		t.Errorf("Unexpected issue: %+v", got)
+	}
+
+	// Partial automata only report the undefined target.
+	_, err = NewFiniteAutomaton(states, alphabet, "A", nil, transitions, WithAllErrors(), WithPartialTransitions())
+	if !errors.As(err, &validationErr) || len(validationErr.Issues) != 1 || validationErr.Issues[0].Kind != IssueUndefinedTarget {
+		t.Errorf("Expected a single undefined-target issue, got %v", err)
+	}
+}
+
+func TestValidate_AllErrorsValidConfig(t *testing.T) {
+	transitions := map[string]map[string]string{"A": {"0": "A"}}
+	if _, err := NewFiniteAutomaton([]string{"A"}, []string{"0"}, "A", nil, transitions, WithAllErrors()); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestBuilder_ReportsAllCompletenessIssues(t *testing.T) {
+	_, err := NewBuilder().
+		AddState("A", "B").
+		AddSymbol("0", "1").
+		SetInitial("A").
+		On("A", "0", "B").
+		Build()
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Issues) != 2 {
+		t.Fatalf("Expected 2 completeness issues, got %v", err)
+	}
+
+	// The builder's own checks and the engine's come back from one Build.
+	_, err = NewBuilder().
+		AddState("A", "B").
+		AddSymbol("0", "0").
+		SetInitial("A").
+		On("A", "0", "B").On("A", "1", "A").
+		On("B", "0", "B").
+		Build()
+	expected := []Issue{
+		{Kind: IssueDuplicateSymbol, Symbol: "0"},
+		{Kind: IssueUnknownSymbol, State: "A", Symbol: "1"},
+	}
+	if !errors.As(err, &validationErr) || !slices.Equal(validationErr.Issues, expected) {
+		t.Errorf("Expected %v, got %v", expected, err)
+	}
+
+	_, err = NewBuilder().
+		AddState("A", "B").
+		AddSymbol("0", "1").
+		SetInitial("A").
+		On("A", "0", "B").On("A", "2", "A").
+		On("B", "0", "B").On("B", "1", "A").
+		Build()
+	expected = []Issue{
+		{Kind: IssueMissingTransition, State: "A", Symbol: "1"},
+		{Kind: IssueUnknownSymbol, State: "A", Symbol: "2"},
+	}
+	if !errors.As(err, &validationErr) || !slices.Equal(validationErr.Issues, expected) {
+		t.Errorf("Expected %v, got %v", expected, err)
+	}
+}
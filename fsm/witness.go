@@ -0,0 +1,109 @@
+package fsm
+
+// Witness generation: concrete example inputs found by breadth-first search
+// over Transitions. Symbols are tried in ascending order, so every function
+// here returns the shortest inputs and breaks ties lexicographically by symbol.
+
+// -----------------------------------------------------------------------------
+// Generic FSM API Methods: Witnesses
+// -----------------------------------------------------------------------------
+
+// ShortestInputs returns, for every state reachable from the initial state,
+// the shortest input that ends in that state. Unreachable states are absent.
+func ShortestInputs(fa *FiniteAutomaton) map[string]string {
+	witnesses, _ := shortestInputs(fa)
+	return witnesses
+}
+
+// ShortestAccepted returns the shortest input accepted by fa.
+// ok is false when the automaton accepts nothing.
+func ShortestAccepted(fa *FiniteAutomaton) (input string, ok bool) {
+	return firstDiscovered(fa, func(state string) bool { return fa.IsAccepting(state) })
+}
+
+// ShortestRejected returns the shortest input over the alphabet that ends in a
+// non-accepting state. ok is false when every reachable state is accepting.
+func ShortestRejected(fa *FiniteAutomaton) (input string, ok bool) {
+	return firstDiscovered(fa, func(state string) bool { return !fa.IsAccepting(state) })
+}
+
+// ShortestStrings returns up to k inputs ending in target, shortest first.
+// Fewer than k are returned when the automaton has fewer such inputs.
+func ShortestStrings(fa *FiniteAutomaton, target string, k int) []string {
+	if k <= 0 || !fa.States[target] {
+		return nil
+	}
+	alphabet := sortedKeys(fa.Alphabet)
+
+	// Only extend prefixes that can still reach the target; this keeps the
+	// search finite when the target's language is finite.
+	predecessors := make(map[string][]string)
+	for from, row := range fa.Transitions {
+		for _, to := range row {
+			predecessors[to] = append(predecessors[to], from)
+		}
+	}
+	canReach := search([]string{target}, predecessors, fa.States)
+
+	type node struct {
+		state string
+		input string
+	}
+	var results []string
+	if !canReach[fa.InitialState] {
+		return nil
+	}
+	queue := []node{{fa.InitialState, ""}}
+	for len(queue) > 0 && len(results) < k {
+		current := queue[0]
+		queue = queue[1:]
+		if current.state == target {
+			results = append(results, current.input)
+		}
+		for _, symbol := range alphabet {
+			next, ok := fa.Transitions[current.state][symbol]
+			if ok && canReach[next] {
+				queue = append(queue, node{next, current.input + symbol})
+			}
+		}
+	}
+	return results
+}
+
+// shortestInputs performs the BFS shared by the witness functions and also
+// returns the states in the order they were discovered.
+func shortestInputs(fa *FiniteAutomaton) (map[string]string, []string) {
+	witnesses := make(map[string]string)
+	if !fa.States[fa.InitialState] {
+		return witnesses, nil
+	}
+	alphabet := sortedKeys(fa.Alphabet)
+
+	witnesses[fa.InitialState] = ""
+	order := []string{fa.InitialState}
+	for i := 0; i < len(order); i++ {
+		current := order[i]
+		for _, symbol := range alphabet {
+			next, ok := fa.Transitions[current][symbol]
+			if !ok || !fa.States[next] {
+				continue
+			}
+			if _, seen := witnesses[next]; !seen {
+				witnesses[next] = witnesses[current] + symbol
+				order = append(order, next)
+			}
+		}
+	}
+	return witnesses, order
+}
+
+// firstDiscovered returns the witness of the first state, in BFS order, matching pred.
+func firstDiscovered(fa *FiniteAutomaton, pred func(state string) bool) (string, bool) {
+	witnesses, order := shortestInputs(fa)
+	for _, state := range order {
+		if pred(state) {
+			return witnesses[state], true
+		}
+	}
+	return "", false
+}
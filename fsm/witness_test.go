@@ -0,0 +1,107 @@
+package fsm
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// loadTrapFA builds the "ends-with-ab" automaton from testdata.
+func loadTrapFA(tb testing.TB) *FiniteAutomaton {
+	tb.Helper()
+	def, err := LoadDefinition(filepath.Join("testdata", "trap.json"))
+	if err != nil {
+		tb.Fatalf("LoadDefinition: %v", err)
+	}
+	fa, err := def.Build()
+	if err != nil {
+		tb.Fatalf("Build: %v", err)
+	}
+	return fa.(*FiniteAutomaton)
+}
+
+// -----------------------------------------------------------------------------
+// UNIT TESTS FOR Witness generation
+// -----------------------------------------------------------------------------
+
+func TestShortestInputs(t *testing.T) {
+	fa := loadTrapFA(t)
+
+	expected := map[string]string{
+		"Start":  "",
+		"SawA":   "a",
+		"Trap":   "c",
+		"Accept": "ab",
+		// "Orphan" is unreachable and therefore has no witness.
+	}
+	if actual := ShortestInputs(fa); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("ShortestInputs: got %v, want %v", actual, expected)
+	}
+
+	// Every witness really ends in its state.
+	for state, input := range expected {
+		if final, err := fa.Run(input); err != nil || final != state {
+			t.Errorf("Run(%q): got (%q, %v), want %q", input, final, err, state)
+		}
+	}
+}
+
+func TestShortestAcceptedAndRejected(t *testing.T) {
+	fa := loadTrapFA(t)
+
+	if input, ok := ShortestAccepted(fa); !ok || input != "ab" {
+		t.Errorf("ShortestAccepted: got (%q, %t), want (\"ab\", true)", input, ok)
+	}
+	if input, ok := ShortestRejected(fa); !ok || input != "" {
+		t.Errorf("ShortestRejected: got (%q, %t), want (\"\", true)", input, ok)
+	}
+
+	// An automaton that accepts everything has no rejected witness,
+	// and one with no accepting states has no accepted witness.
+	fa.AcceptingStates = map[string]bool{"Start": true, "SawA": true, "Accept": true, "Trap": true}
+	if input, ok := ShortestRejected(fa); ok {
+		t.Errorf("ShortestRejected on all-accepting FA: got %q, want none", input)
+	}
+	fa.AcceptingStates = map[string]bool{}
+	if input, ok := ShortestAccepted(fa); ok {
+		t.Errorf("ShortestAccepted on FA without accepting states: got %q, want none", input)
+	}
+}
+
+func TestShortestStrings(t *testing.T) {
+	fa := loadTrapFA(t)
+
+	// Finite language: A -x-> B -x-> C, C loops. Only "x" ends in B.
+	finite := &FiniteAutomaton{
+		States:       map[string]bool{"A": true, "B": true, "C": true},
+		Alphabet:     map[string]bool{"x": true},
+		InitialState: "A",
+		Transitions: map[string]map[string]string{
+			"A": {"x": "B"}, "B": {"x": "C"}, "C": {"x": "C"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		fa       *FiniteAutomaton
+		target   string
+		k        int
+		expected []string
+	}{
+		{"Accept_Three", fa, "Accept", 3, []string{"ab", "aab", "bab"}},
+		{"Start_Two", fa, "Start", 2, []string{"", "b"}},
+		{"Unreachable", fa, "Orphan", 3, nil},
+		{"UnknownState", fa, "Nowhere", 3, nil},
+		{"ZeroK", fa, "Accept", 0, nil},
+		{"FiniteLanguage", finite, "B", 5, []string{"x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := ShortestStrings(tt.fa, tt.target, tt.k)
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("ShortestStrings(%q, %d): got %q, want %q", tt.target, tt.k, actual, tt.expected)
+			}
+		})
+	}
+}
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strings"
+	"modulo_three_advanced/fsm"
 	"modulo_three_advanced/mod3" 
 )
 
@@ -10,6 +13,11 @@ func main() {
 	// The standard logic:
 	// If len(os.Args) > 1, a user provided an argument (at index 1) to process.
 	if len(os.Args) > 1 {
+		// "lint <file>..." analyses FSM definition files instead of computing a remainder.
+		if os.Args[1] == "lint" {
+			os.Exit(runLint(os.Args[2:]))
+		}
+
 		// Use os.Args[1], which is the first argument supplied by the user.
 		processInput(os.Args[1])
 		return
@@ -78,4 +86,75 @@ func runDemo(){
 	} else {
 		fmt.Printf("  Result: Success Execution \n  Remainder: %d\n", remainderInvalid)
 	}
-}
\ No newline at end of file
+}
+
+// runLint validates and analyses each definition file, printing a report per file.
+// It returns the process exit code: 0 when every file is clean, 1 otherwise.
+func runLint(paths []string) int {
+	if len(paths) == 0 {
+		fmt.Println("usage: lint <definition.json>...")
+		return 2
+	}
+
+	exitCode := 0
+	for _, path := range paths {
+		fmt.Printf("--- Lint: %s ---\n", path)
+
+		def, err := fsm.LoadDefinition(path)
+		if err != nil {
+			fmt.Printf("  ERROR: %v\n", err)
+			exitCode = 1
+			continue
+		}
+		automaton, err := def.Build(fsm.WithAllErrors())
+		var validationErr *fsm.ValidationError
+		if errors.As(err, &validationErr) {
+			fmt.Print(indent(validationErr.Report()))
+			exitCode = 1
+			continue
+		}
+		if err != nil {
+			fmt.Printf("  ERROR: %v\n", err)
+			exitCode = 1
+			continue
+		}
+
+		report := fsm.Analyze(automaton.(*fsm.FiniteAutomaton))
+		printReport(report)
+		if report.HasFindings() {
+			exitCode = 1
+		}
+	}
+	return exitCode
+}
+
+// printReport renders an analysis report, one finding category per line.
+func printReport(r fsm.AnalysisReport) {
+	if !r.HasFindings() {
+		fmt.Println("  OK: no findings")
+	}
+	printFinding("Unreachable states", r.Unreachable)
+	printFinding("Dead states", r.Dead)
+	printFinding("Sink states", r.Sink)
+	printFinding("Self-loop-only states", r.SelfLoopOnly)
+	printFinding("Unused symbols", r.UnusedSymbols)
+
+	components := make([]string, 0, len(r.Components))
+	for _, c := range r.Components {
+		components = append(components, "{"+strings.Join(c, ", ")+"}")
+	}
+	fmt.Printf("  Strongly connected components: %s\n", strings.Join(components, " "))
+}
+
+func printFinding(label string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Printf("  WARNING: %s: %s\n", label, strings.Join(items, ", "))
+}
+
+// indent prefixes every line of a multi-line report with two spaces.
+func indent(text string) string {
+	lines := strings.SplitAfter(strings.TrimSuffix(text, "\n"), "\n")
+	return "  " + strings.Join(lines, "  ") + "\n"
+}
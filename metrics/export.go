@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"bufio"
+	"expvar"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Metric names used by the Prometheus exporter.
+const (
+	metricCalls       = "modulo_calls_total"
+	metricErrors      = "modulo_errors_total"
+	metricStateVisits = "modulo_state_visits_total"
+	metricInputLength = "modulo_input_length_bytes"
+	metricLatency     = "modulo_latency_seconds"
+)
+
+// -----------------------------------------------------------------------------
+// Prometheus text exposition format
+// -----------------------------------------------------------------------------
+
+// WritePrometheus writes snap in the Prometheus text exposition format (0.0.4).
+// Components, error kinds and states are sorted so the output is deterministic.
+func WritePrometheus(w io.Writer, snap Snapshot) error {
+	bw := bufio.NewWriter(w)
+	components := slices.Sorted(maps.Keys(snap))
+
+	fmt.Fprintf(bw, "# HELP %s Number of instrumented calls.\n# TYPE %s counter\n", metricCalls, metricCalls)
+	for _, name := range components {
+		fmt.Fprintf(bw, "%s{component=%s} %d\n", metricCalls, quote(name), snap[name].Calls)
+	}
+
+	fmt.Fprintf(bw, "# HELP %s Number of failed calls by error kind.\n# TYPE %s counter\n", metricErrors, metricErrors)
+	for _, name := range components {
+		errs := snap[name].Errors
+		for _, kind := range slices.Sorted(maps.Keys(errs)) {
+			fmt.Fprintf(bw, "%s{component=%s,kind=%s} %d\n", metricErrors, quote(name), quote(kind), errs[kind])
+		}
+	}
+
+	fmt.Fprintf(bw, "# HELP %s Number of times each state was entered.\n# TYPE %s counter\n", metricStateVisits, metricStateVisits)
+	for _, name := range components {
+		visits := snap[name].StateVisits
+		for _, state := range slices.Sorted(maps.Keys(visits)) {
+			fmt.Fprintf(bw, "%s{component=%s,state=%s} %d\n", metricStateVisits, quote(name), quote(state), visits[state])
+		}
+	}
+
+	writeHistogram(bw, metricInputLength, "Input length of instrumented calls.", components, snap,
+		func(s ComponentStats) Histogram { return s.InputLength })
+	writeHistogram(bw, metricLatency, "Latency of instrumented calls.", components, snap,
+		func(s ComponentStats) Histogram { return s.Latency })
+
+	return bw.Flush()
+}
+
+func writeHistogram(w io.Writer, metric, help string, components []string, snap Snapshot, pick func(ComponentStats) Histogram) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", metric, help, metric)
+	for _, name := range components {
+		h := pick(snap[name])
+		for i, bound := range h.Bounds {
+			fmt.Fprintf(w, "%s_bucket{component=%s,le=%s} %d\n", metric, quote(name), quote(formatFloat(bound)), h.Counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket{component=%s,le=\"+Inf\"} %d\n", metric, quote(name), h.Counts[len(h.Bounds)])
+		fmt.Fprintf(w, "%s_sum{component=%s} %s\n", metric, quote(name), formatFloat(h.Sum))
+		fmt.Fprintf(w, "%s_count{component=%s} %d\n", metric, quote(name), h.Count)
+	}
+}
+
+// ServeHTTP serves the current snapshot in the Prometheus text format,
+// so a Collector can be mounted directly on a /metrics route.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := WritePrometheus(w, c.Snapshot()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// quote renders a label value with Prometheus escaping (backslash, quote, newline).
+func quote(v string) string {
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, "\n", `\n`)
+	v = strings.ReplaceAll(v, `"`, `\"`)
+	return `"` + v + `"`
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// -----------------------------------------------------------------------------
+// expvar adapter
+// -----------------------------------------------------------------------------
+
+// Var returns an expvar.Var rendering the current snapshot as JSON.
+func (c *Collector) Var() expvar.Var {
+	return expvar.Func(func() any { return c.Snapshot() })
+}
+
+// Publish registers the collector under name in the expvar registry.
+// Like expvar.Publish, it panics if name is already registered.
+func (c *Collector) Publish(name string) {
+	expvar.Publish(name, c.Var())
+}
@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"time"
+
+	"modulo_three_advanced/fsm"
+	"modulo_three_advanced/mod3"
+)
+
+// Option configures an instrumented wrapper.
+type Option func(*options)
+
+type options struct {
+	now func() time.Time // clock used for latency
+}
+
+func applyOptions(opts []Option) options {
+	o := options{now: time.Now}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithClock measures latency with now instead of time.Now, e.g. to make
+// recorded latencies deterministic in tests.
+func WithClock(now func() time.Time) Option {
+	return func(o *options) { o.now = now }
+}
+
+// -----------------------------------------------------------------------------
+// Automaton wrapper
+// -----------------------------------------------------------------------------
+
+type instrumentedAutomaton struct {
+	fsm.Automaton
+	name     string
+	recorder Recorder
+	now      func() time.Time
+}
+
+// InstrumentAutomaton wraps a so every Run and Execute is recorded under
+// component name, with the per-state visit counts Execute reports.
+func InstrumentAutomaton(a fsm.Automaton, name string, r Recorder, opts ...Option) fsm.Automaton {
+	return &instrumentedAutomaton{Automaton: a, name: name, recorder: r, now: applyOptions(opts).now}
+}
+
+func (ia *instrumentedAutomaton) Run(input string) (string, error) {
+	result, err := ia.Execute(input)
+	if err != nil {
+		return "", err
+	}
+	return result.FinalState, nil
+}
+
+func (ia *instrumentedAutomaton) Execute(input string) (fsm.RunResult, error) {
+	start := ia.now()
+	result, err := ia.Automaton.Execute(input)
+	ia.recorder.Record(Observation{
+		Component:   ia.name,
+		InputLength: len(input),
+		Latency:     ia.now().Sub(start),
+		ErrorKind:   ErrorKind(err),
+		StateVisits: result.Visits,
+	})
+	return result, err
+}
+
+// -----------------------------------------------------------------------------
+// Calculator wrapper
+// -----------------------------------------------------------------------------
+
+type instrumentedCalculator struct {
+	mod3.ModuloCalculator
+	name     string
+	recorder Recorder
+	now      func() time.Time
+}
+
+// InstrumentCalculator wraps c so every Calculate is recorded under component name.
+// Calculators do not expose their states, so no state visits are recorded.
+func InstrumentCalculator(c mod3.ModuloCalculator, name string, r Recorder, opts ...Option) mod3.ModuloCalculator {
+	return &instrumentedCalculator{ModuloCalculator: c, name: name, recorder: r, now: applyOptions(opts).now}
+}
+
+func (ic *instrumentedCalculator) Calculate(input string) (int, error) {
+	start := ic.now()
+	remainder, err := ic.ModuloCalculator.Calculate(input)
+	ic.recorder.Record(Observation{
+		Component:   ic.name,
+		InputLength: len(input),
+		Latency:     ic.now().Sub(start),
+		ErrorKind:   ErrorKind(err),
+	})
+	return remainder, err
+}
@@ -0,0 +1,191 @@
+// Package metrics provides optional runtime instrumentation for automata and
+// calculators. Wrappers record observations into a Recorder; the in-memory
+// Collector aggregates them and exports a Prometheus text-format page or an
+// expvar variable, so metrics can be asserted on without a live scraper.
+package metrics
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"modulo_three_advanced/fsm"
+	"modulo_three_advanced/mod3"
+)
+
+// Default histogram bucket upper bounds.
+var (
+	// DefaultLengthBuckets bound input lengths in bytes.
+	DefaultLengthBuckets = []float64{0, 8, 32, 64, 256, 1024, 4096, 65536, 1 << 20}
+	// DefaultLatencyBuckets bound call latencies in seconds.
+	DefaultLatencyBuckets = []float64{1e-6, 1e-5, 1e-4, 1e-3, 1e-2, 0.1, 1}
+)
+
+// Observation describes a single instrumented call.
+type Observation struct {
+	Component   string         // name given to the wrapper, e.g. "mod3"
+	InputLength int            // length of the input in bytes
+	Latency     time.Duration  // wall time spent in the call
+	ErrorKind   string         // "" on success, otherwise see ErrorKind
+	StateVisits map[string]int // states entered during the call; nil when unknown
+}
+
+// Recorder receives observations from instrumented wrappers. Implementations
+// must be safe for concurrent use.
+type Recorder interface {
+	Record(obs Observation)
+}
+
+// ErrorKind classifies err into a stable, low-cardinality label value.
+func ErrorKind(err error) string {
+	var runErr *fsm.RunError
+	var abortErr *fsm.AbortError
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &runErr):
+		return string(runErr.Kind)
+	case errors.As(err, &abortErr):
+		return string(abortErr.Kind)
+	case errors.Is(err, mod3.ErrInvalidInput):
+		return "invalid_input"
+	case errors.Is(err, mod3.ErrNonAccepting):
+		return "non_accepting"
+	case errors.Is(err, mod3.ErrUnknownState):
+		return "unknown_state"
+	case errors.Is(err, mod3.ErrInvalidWidth):
+		return "invalid_width"
+	case errors.Is(err, mod3.ErrUnsupportedModulus):
+		return "unsupported_modulus"
+	default:
+		return "other"
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Collector: the in-memory Recorder
+// -----------------------------------------------------------------------------
+
+// Histogram is a cumulative-bucket histogram snapshot.
+type Histogram struct {
+	Bounds []float64 // upper bounds, ascending; an implicit +Inf bucket follows
+	Counts []uint64  // Counts[i] = observations <= Bounds[i]; len(Bounds)+1 entries, last is +Inf
+	Sum    float64
+	Count  uint64
+}
+
+func newHistogram(bounds []float64) *Histogram {
+	return &Histogram{Bounds: bounds, Counts: make([]uint64, len(bounds)+1)}
+}
+
+func (h *Histogram) observe(v float64) {
+	for i, bound := range h.Bounds {
+		if v <= bound {
+			h.Counts[i]++
+		}
+	}
+	h.Counts[len(h.Bounds)]++
+	h.Sum += v
+	h.Count++
+}
+
+func (h *Histogram) clone() Histogram {
+	return Histogram{Bounds: h.Bounds, Counts: slices.Clone(h.Counts), Sum: h.Sum, Count: h.Count}
+}
+
+// ComponentStats aggregates every observation for one component.
+type ComponentStats struct {
+	Calls       uint64
+	Errors      map[string]uint64 // by error kind
+	StateVisits map[string]uint64 // by state
+	InputLength Histogram
+	Latency     Histogram // seconds
+}
+
+// Snapshot is a point-in-time copy of a Collector, keyed by component.
+type Snapshot map[string]ComponentStats
+
+// Collector is a Recorder that aggregates observations in memory.
+// The zero value is not usable; create one with NewCollector.
+type Collector struct {
+	mu             sync.Mutex
+	lengthBuckets  []float64
+	latencyBuckets []float64
+	components     map[string]*componentState
+}
+
+type componentState struct {
+	calls       uint64
+	errors      map[string]uint64
+	stateVisits map[string]uint64
+	inputLength *Histogram
+	latency     *Histogram
+}
+
+// NewCollector returns a Collector using DefaultLengthBuckets and DefaultLatencyBuckets.
+func NewCollector() *Collector {
+	return NewCollectorWithBuckets(DefaultLengthBuckets, DefaultLatencyBuckets)
+}
+
+// NewCollectorWithBuckets returns a Collector with custom histogram bounds,
+// given in bytes and seconds respectively.
+func NewCollectorWithBuckets(lengthBuckets, latencyBuckets []float64) *Collector {
+	return &Collector{
+		lengthBuckets:  slices.Sorted(slices.Values(lengthBuckets)),
+		latencyBuckets: slices.Sorted(slices.Values(latencyBuckets)),
+		components:     make(map[string]*componentState),
+	}
+}
+
+// Record implements Recorder.
+func (c *Collector) Record(obs Observation) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cs, ok := c.components[obs.Component]
+	if !ok {
+		cs = &componentState{
+			errors:      make(map[string]uint64),
+			stateVisits: make(map[string]uint64),
+			inputLength: newHistogram(c.lengthBuckets),
+			latency:     newHistogram(c.latencyBuckets),
+		}
+		c.components[obs.Component] = cs
+	}
+
+	cs.calls++
+	if obs.ErrorKind != "" {
+		cs.errors[obs.ErrorKind]++
+	}
+	for state, visits := range obs.StateVisits {
+		cs.stateVisits[state] += uint64(visits)
+	}
+	cs.inputLength.observe(float64(obs.InputLength))
+	cs.latency.observe(obs.Latency.Seconds())
+}
+
+// Snapshot returns a deep copy of the aggregated metrics.
+func (c *Collector) Snapshot() Snapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	snap := make(Snapshot, len(c.components))
+	for name, cs := range c.components {
+		stats := ComponentStats{
+			Calls:       cs.calls,
+			Errors:      make(map[string]uint64, len(cs.errors)),
+			StateVisits: make(map[string]uint64, len(cs.stateVisits)),
+			InputLength: cs.inputLength.clone(),
+			Latency:     cs.latency.clone(),
+		}
+		for k, v := range cs.errors {
+			stats.Errors[k] = v
+		}
+		for k, v := range cs.stateVisits {
+			stats.StateVisits[k] = v
+		}
+		snap[name] = stats
+	}
+	return snap
+}
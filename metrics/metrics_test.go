@@ -0,0 +1,193 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"modulo_three_advanced/fsm"
+	"modulo_three_advanced/mod3"
+)
+
+// fakeClock advances by one millisecond on every call, making latencies deterministic.
+func fakeClock() Option {
+	current := time.Unix(0, 0)
+	return WithClock(func() time.Time {
+		current = current.Add(time.Millisecond)
+		return current
+	})
+}
+
+// setupModThreeFA builds the concrete Mod-Three automaton.
+func setupModThreeFA(t *testing.T) fsm.Automaton {
+	t.Helper()
+	cfg := mod3.GetModThreeConfig()
+	fa, err := fsm.NewFiniteAutomaton(cfg.States, cfg.Alphabet, cfg.InitialState, cfg.AcceptingStates, cfg.Transitions)
+	if err != nil {
+		t.Fatalf("Failed to build Mod-Three automaton: %v", err)
+	}
+	return fa
+}
+
+// -----------------------------------------------------------------------------
+// 1. UNIT TEST FOR ErrorKind
+// -----------------------------------------------------------------------------
+
+func TestErrorKind(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected string
+	}{
+		{nil, ""},
+		{&fsm.RunError{Kind: fsm.KindInvalidSymbol}, "invalid_symbol"},
+		{fmt.Errorf("wrapped: %w", &fsm.RunError{Kind: fsm.KindMissingRules}), "missing_rules"},
+		{fmt.Errorf("%w: 1A", mod3.ErrInvalidInput), "invalid_input"},
+		{fmt.Errorf("%w: S1", mod3.ErrNonAccepting), "non_accepting"},
+		{fmt.Errorf("%w: S9", mod3.ErrUnknownState), "unknown_state"},
+		{fmt.Errorf("%w: got 3 bits, want 8", mod3.ErrInvalidWidth), "invalid_width"},
+		{fmt.Errorf("%w: 7", mod3.ErrUnsupportedModulus), "unsupported_modulus"},
+		{&fsm.AbortError{Kind: fsm.KindStepLimit, Limit: 8}, "step_limit"},
+		{&fsm.AbortError{Kind: fsm.KindCanceled, Err: context.Canceled}, "canceled"},
+		{errors.New("boom"), "other"},
+	}
+	for _, tt := range tests {
+		if actual := ErrorKind(tt.err); actual != tt.expected {
+			t.Errorf("ErrorKind(%v): got %q, want %q", tt.err, actual, tt.expected)
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+// 2. UNIT TESTS FOR the wrappers
+// -----------------------------------------------------------------------------
+
+func TestInstrumentAutomaton(t *testing.T) {
+	collector := NewCollector()
+	fa := InstrumentAutomaton(setupModThreeFA(t), "fa", collector, fakeClock())
+
+	// S0 -1-> S1 -1-> S0 -0-> S0 -1-> S1
+	if state, err := fa.Run("1101"); err != nil || state != mod3.StateS1 {
+		t.Fatalf("Run(\"1101\"): got (%q, %v)", state, err)
+	}
+	if _, err := fa.Run("12"); err == nil {
+		t.Fatal("Run(\"12\") should fail")
+	}
+	if !fa.ValidateInput("01") || !fa.IsAccepting(mod3.StateS2) {
+		t.Error("Wrapped automaton must delegate ValidateInput and IsAccepting")
+	}
+
+	stats := collector.Snapshot()["fa"]
+	if stats.Calls != 2 {
+		t.Errorf("Calls: got %d, want 2", stats.Calls)
+	}
+	if stats.Errors["invalid_symbol"] != 1 {
+		t.Errorf("Errors: got %v, want one invalid_symbol", stats.Errors)
+	}
+	// The failed run still entered S0 and S1 before the bad symbol.
+	if stats.StateVisits[mod3.StateS0] != 4 || stats.StateVisits[mod3.StateS1] != 3 {
+		t.Errorf("StateVisits: got %v, want S0=4 S1=3", stats.StateVisits)
+	}
+	if stats.Latency.Count != 2 || stats.Latency.Sum != 0.002 {
+		t.Errorf("Latency: got count %d sum %g, want 2 and 0.002", stats.Latency.Count, stats.Latency.Sum)
+	}
+	if stats.InputLength.Sum != 6 {
+		t.Errorf("InputLength sum: got %g, want 6", stats.InputLength.Sum)
+	}
+
+	// Execute is recorded like Run and returns the visits it records.
+	result, err := fa.Execute("1101")
+	if err != nil || result.Visits[mod3.StateS0] != 3 || result.Visits[mod3.StateS1] != 2 {
+		t.Fatalf("Execute(\"1101\"): got (%+v, %v)", result, err)
+	}
+	if stats := collector.Snapshot()["fa"]; stats.Calls != 3 || stats.StateVisits[mod3.StateS0] != 7 {
+		t.Errorf("After Execute: got %d calls and %v, want 3 calls and S0=7", stats.Calls, stats.StateVisits)
+	}
+}
+
+func TestInstrumentCalculator(t *testing.T) {
+	collector := NewCollector()
+	calc, _ := mod3.NewModThreeCalculator(mod3.GetModThreeConfig())
+	calc = InstrumentCalculator(calc, "mod3", collector, fakeClock())
+
+	for _, input := range []string{"1101", "1A01", "", "111"} {
+		calc.Calculate(input)
+	}
+
+	stats := collector.Snapshot()["mod3"]
+	if stats.Calls != 4 || stats.Errors["invalid_input"] != 1 || len(stats.StateVisits) != 0 {
+		t.Errorf("Unexpected calculator stats: %+v", stats)
+	}
+	// Lengths 4, 4, 0, 3 fall in buckets le=0 (1) and le=8 (4 cumulative).
+	if stats.InputLength.Counts[0] != 1 || stats.InputLength.Counts[1] != 4 {
+		t.Errorf("InputLength buckets: got %v", stats.InputLength.Counts)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// 3. UNIT TESTS FOR the exporters
+// -----------------------------------------------------------------------------
+
+func TestWritePrometheus(t *testing.T) {
+	collector := NewCollectorWithBuckets([]float64{4, 1}, []float64{0.01})
+	collector.Record(Observation{Component: "mod3", InputLength: 3, Latency: 5 * time.Millisecond, StateVisits: map[string]int{"S0": 2}})
+	collector.Record(Observation{Component: "mod3", InputLength: 9, Latency: time.Second, ErrorKind: "invalid_input"})
+	collector.Record(Observation{Component: `we"ird`, InputLength: 0})
+
+	var sb strings.Builder
+	if err := WritePrometheus(&sb, collector.Snapshot()); err != nil {
+		t.Fatalf("WritePrometheus failed: %v", err)
+	}
+	out := sb.String()
+
+	for _, line := range []string{
+		"# TYPE modulo_calls_total counter",
+		`modulo_calls_total{component="mod3"} 2`,
+		`modulo_calls_total{component="we\"ird"} 1`,
+		`modulo_errors_total{component="mod3",kind="invalid_input"} 1`,
+		`modulo_state_visits_total{component="mod3",state="S0"} 2`,
+		"# TYPE modulo_input_length_bytes histogram",
+		`modulo_input_length_bytes_bucket{component="mod3",le="1"} 0`,
+		`modulo_input_length_bytes_bucket{component="mod3",le="4"} 1`,
+		`modulo_input_length_bytes_bucket{component="mod3",le="+Inf"} 2`,
+		`modulo_input_length_bytes_sum{component="mod3"} 12`,
+		`modulo_latency_seconds_bucket{component="mod3",le="0.01"} 1`,
+		`modulo_latency_seconds_count{component="mod3"} 2`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("Prometheus output missing line %q\n%s", line, out)
+		}
+	}
+
+	// The HTTP handler serves the same page.
+	rec := httptest.NewRecorder()
+	collector.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if rec.Body.String() != out || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Errorf("ServeHTTP mismatch: content type %q", rec.Header().Get("Content-Type"))
+	}
+}
+
+func TestExpvarAdapter(t *testing.T) {
+	collector := NewCollector()
+	collector.Record(Observation{Component: "mod3", InputLength: 4, ErrorKind: "non_accepting"})
+
+	var decoded map[string]ComponentStats
+	if err := json.Unmarshal([]byte(collector.Var().String()), &decoded); err != nil {
+		t.Fatalf("expvar output is not JSON: %v", err)
+	}
+	if decoded["mod3"].Calls != 1 || decoded["mod3"].Errors["non_accepting"] != 1 {
+		t.Errorf("Unexpected expvar snapshot: %+v", decoded)
+	}
+
+	collector.Publish("modulo_metrics_test")
+	defer func() {
+		if recover() == nil {
+			t.Error("Publishing the same name twice should panic like expvar.Publish")
+		}
+	}()
+	collector.Publish("modulo_metrics_test")
+}
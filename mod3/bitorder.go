@@ -0,0 +1,97 @@
+package mod3
+
+import (
+	"fmt"
+
+	"modulo_three_advanced/fsm/typed"
+)
+
+// BitOrder is the order in which a calculator reads the bits of its input.
+type BitOrder int
+
+const (
+	// MSBFirst reads the most significant bit first, as written. It is the default.
+	MSBFirst BitOrder = iota
+	// LSBFirst reads the least significant bit first, as emitted by some
+	// hardware sources, without reversing the input.
+	LSBFirst
+)
+
+func (b BitOrder) String() string {
+	if b == LSBFirst {
+		return "lsb-first"
+	}
+	return "msb-first"
+}
+
+// LSB-first states track the remainder so far (R) and the residue of the
+// place value of the next bit, 2^k mod 3 (P), which alternates 1, 2, 1, ...
+const (
+	StateR0P1 = "R0P1"
+	StateR0P2 = "R0P2"
+	StateR1P1 = "R1P1"
+	StateR1P2 = "R1P2"
+	StateR2P1 = "R2P1"
+	StateR2P2 = "R2P2"
+)
+
+// GetModThreeLSBConfig returns the LSB-first Mod-Three automaton. Reading
+// bit b in state (R, P) moves to ((R + b×P) mod 3, 2×P mod 3), so the final
+// R is the remainder of the whole input.
+func GetModThreeLSBConfig() ModThreeFSMConfig {
+	var states []string
+	transitions := make(map[string]map[string]string)
+	for _, s := range lsbStates() {
+		name := s.String()
+		states = append(states, name)
+		transitions[name] = map[string]string{
+			Symbol0: s.next(0).String(),
+			Symbol1: s.next(1).String(),
+		}
+	}
+	return ModThreeFSMConfig{
+		States:       states,
+		Alphabet:     []string{Symbol0, Symbol1},
+		InitialState: StateR0P1,
+		// As in the MSB-first design, every final state carries a remainder.
+		AcceptingStates: states,
+		Transitions:     transitions,
+	}
+}
+
+// LSBState is a typed LSB-first state: the remainder so far and 2^k mod 3.
+type LSBState struct {
+	Remainder int
+	Power     int
+}
+
+// next is the state after reading bit.
+func (s LSBState) next(bit int) LSBState {
+	return LSBState{Remainder: (s.Remainder + bit*s.Power) % 3, Power: (2 * s.Power) % 3}
+}
+
+// lsbStates lists the six states in the order of the StateRxPy constants.
+func lsbStates() []LSBState {
+	var states []LSBState
+	for r := range 3 {
+		for _, p := range []int{1, 2} {
+			states = append(states, LSBState{Remainder: r, Power: p})
+		}
+	}
+	return states
+}
+
+// String names the state like the StateRxPy constants.
+func (s LSBState) String() string {
+	return fmt.Sprintf("R%dP%d", s.Remainder, s.Power)
+}
+
+// LSBRemainderAutomaton is the typed counterpart of GetModThreeLSBConfig.
+func LSBRemainderAutomaton() (*typed.FiniteAutomaton[LSBState, int], error) {
+	states := lsbStates()
+	transitions := make(map[LSBState]map[int]LSBState, len(states))
+	for _, s := range states {
+		transitions[s] = map[int]LSBState{0: s.next(0), 1: s.next(1)}
+	}
+	return typed.New(states, []int{0, 1}, LSBState{Remainder: 0, Power: 1}, states, transitions)
+}
@@ -0,0 +1,140 @@
+package mod3
+
+import (
+	"errors"
+	"math/big"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"modulo_three_advanced/fsm"
+)
+
+// -----------------------------------------------------------------------------
+// UNIT TESTS FOR WithBitOrder(LSBFirst)
+// -----------------------------------------------------------------------------
+
+func reverse(s string) string {
+	b := []byte(s)
+	slices.Reverse(b)
+	return string(b)
+}
+
+func TestLSBConfig(t *testing.T) {
+	cfg := GetModThreeLSBConfig()
+	fa, err := buildAutomaton(cfg)
+	if err != nil {
+		t.Fatalf("GetModThreeLSBConfig is invalid: %v", err)
+	}
+	if report := fsm.Analyze(fa); len(report.Unreachable) != 0 || len(report.Dead) != 0 {
+		t.Errorf("Unexpected analysis findings: %+v", report)
+	}
+	// 1011 LSB-first is 0b1101 = 13: R0P1 -1-> R1P2 -0-> R1P1 -1-> R2P2 -1-> R1P1
+	if state, err := fa.Run("1011"); err != nil || state != StateR1P1 {
+		t.Errorf("Run(1011): got (%q, %v), want %s", state, err, StateR1P1)
+	}
+
+	found := meanings(fa.InitialState, transitionOnBit(fa.Freeze()), lsbSpace)
+	for _, state := range cfg.States {
+		if len(found[state]) != 1 || !found[state][LSBState{Remainder: int(state[1] - '0'), Power: int(state[3] - '0')}] {
+			t.Errorf("meanings(%s): got %v", state, found[state])
+		}
+	}
+	if MSBFirst.String() != "msb-first" || LSBFirst.String() != "lsb-first" {
+		t.Error("Unexpected BitOrder names")
+	}
+}
+
+func TestLSBFirst(t *testing.T) {
+	tests := []struct {
+		encoding Encoding
+		input    string
+		expected int
+		wantErr  error
+	}{
+		{Unsigned, "", 0, nil},
+		{Unsigned, "1011", 1, nil}, // 13
+		{Unsigned, "0111", 2, nil}, // 14
+		{Unsigned, "1", 1, nil},
+		{Unsigned, "01", 2, nil},
+		{Unsigned, "10x1", -1, ErrInvalidInput},
+		{SignMagnitude, "-1011", 2, nil},    // -13
+		{TwosComplement(4), "1111", 2, nil}, // -1
+		{TwosComplement(4), "1101", 1, nil}, // 0b1011 = -5
+		{TwosComplement(4), "1010", 2, nil}, // 0b0101 = 5
+		{TwosComplement(4), "101", -1, ErrInvalidWidth},
+	}
+
+	for _, tt := range tests {
+		for name, calc := range calculatorFamily(t, WithBitOrder(LSBFirst), WithEncoding(tt.encoding)) {
+			actual, err := calc.Calculate(tt.input)
+			if actual != tt.expected || !errors.Is(err, tt.wantErr) {
+				t.Errorf("%s %v Calculate(%q): got (%d, %v), want (%d, %v)", name, tt.encoding, tt.input, actual, err, tt.expected, tt.wantErr)
+			}
+		}
+	}
+}
+
+func TestLSBFirst_Config(t *testing.T) {
+	renamed := GetModThreeConfig()
+	renamed.States = []string{"A", "B", "C"}
+	renamed.InitialState = "A"
+	renamed.AcceptingStates = []string{"A", "B", "C"}
+	renamed.Transitions = map[string]map[string]string{
+		"A": {"0": "A", "1": "B"},
+		"B": {"0": "C", "1": "A"},
+		"C": {"0": "B", "1": "C"},
+	}
+
+	tests := []struct {
+		name string
+		cfg  ModThreeFSMConfig
+	}{
+		{"MSB-first default", GetModThreeConfig()},
+		{"MSB-first renamed", renamed},
+		{"LSB-first default", GetModThreeLSBConfig()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The option alone is enough, whichever default config is passed.
+			calc, err := NewModThreeCalculator(tt.cfg, WithBitOrder(LSBFirst))
+			if err != nil {
+				t.Fatalf("NewModThreeCalculator failed: %v", err)
+			}
+			for input, expected := range map[string]int{"1011": 1, "0111": 2, "01": 2, "": 0} {
+				if actual, err := calc.Calculate(input); err != nil || actual != expected {
+					t.Errorf("Calculate(%q): got (%d, %v), want %d", input, actual, err, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestLSBFirst_Adapter(t *testing.T) {
+	calc, err := NewRemainderCalculator(WithBitOrder(LSBFirst))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	adapter := calc.Adapter()
+	if state, err := adapter.Run("1011"); err != nil || state != StateR1P1 || !adapter.IsAccepting(state) {
+		t.Errorf("Run(1011): got (%q, %v), want accepting %s", state, err, StateR1P1)
+	}
+}
+
+func TestProperty_LSBFirstMatchesReversedMSBFirst(t *testing.T) {
+	rng := rand.New(rand.NewPCG(7, 3))
+	msb := calculatorFamily(t)
+	lsb := calculatorFamily(t, WithBitOrder(LSBFirst))
+
+	for range 300 {
+		input := randomBits(rng, 1+rng.IntN(256))
+		n, _ := new(big.Int).SetString(input, 2)
+		for name := range msb {
+			want, _ := msb[name].Calculate(input)
+			got, err := lsb[name].Calculate(reverse(input))
+			if err != nil || got != want || got != bigMod(n, 3) {
+				t.Fatalf("%s: LSB-first Calculate(%q) = (%d, %v), MSB-first = %d", name, reverse(input), got, err, want)
+			}
+		}
+	}
+}
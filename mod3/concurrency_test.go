@@ -0,0 +1,94 @@
+package mod3
+
+import (
+	"bytes"
+	"log/slog"
+	"math/big"
+	"math/rand/v2"
+	"reflect"
+	"sync"
+	"testing"
+
+	"modulo_three_advanced/tracing"
+)
+
+// -----------------------------------------------------------------------------
+// CONCURRENCY TESTS (run with -race)
+// -----------------------------------------------------------------------------
+
+// lockedBuffer serialises writes so the logger's sink is not the race under test.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func TestConcurrentCalculate(t *testing.T) {
+	rng := rand.New(rand.NewPCG(4, 7))
+	inputs := make([]string, 64)
+	expected := make([]int, len(inputs))
+	for i := range inputs {
+		inputs[i] = randomBits(rng, 1+rng.IntN(300))
+		n, _ := new(big.Int).SetString(inputs[i], 2)
+		expected[i] = bigMod(n, 3)
+	}
+	inputs = append(inputs, "10a1")
+	expected = append(expected, -1)
+
+	// One shared instance of each calculator, with logging and tracing on.
+	logger := slog.New(slog.NewTextHandler(&lockedBuffer{}, nil))
+	family := calculatorFamily(t, WithLogger(logger), WithTracer(tracing.NewRecorder()))
+	multi, err := NewMultiModCalculator([]int{3, 4}, WithLogger(logger))
+	if err != nil {
+		t.Fatalf("NewMultiModCalculator failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for g := range 32 {
+		wg.Go(func() {
+			for i := range 200 {
+				k := (g*7 + i) % len(inputs)
+				for name, calc := range family {
+					if actual, _ := calc.Calculate(inputs[k]); actual != expected[k] {
+						t.Errorf("%s Calculate(%q): got %d, want %d", name, inputs[k], actual, expected[k])
+						return
+					}
+					if packed, ok := calc.(PackedCalculator); ok && expected[k] >= 0 {
+						n, _ := new(big.Int).SetString(inputs[k], 2)
+						if actual, err := packed.CalculateBig(n); err != nil || actual != expected[k] {
+							t.Errorf("%s CalculateBig(%s): got (%d, %v), want %d", name, n, actual, err, expected[k])
+							return
+						}
+					}
+				}
+				if remainders, _ := multi.Remainders(inputs[k]); expected[k] >= 0 && remainders[0] != expected[k] {
+					t.Errorf("MultiMod Remainders(%q): got %v, want %d first", inputs[k], remainders, expected[k])
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+}
+
+func TestNewModThreeCalculator_SnapshotsConfig(t *testing.T) {
+	cfg := GetModThreeConfig()
+	calc, err := NewModThreeCalculator(cfg)
+	if err != nil {
+		t.Fatalf("NewModThreeCalculator failed: %v", err)
+	}
+
+	// Rewriting the caller's table afterwards must not reach the calculator.
+	cfg.Transitions[StateS0][Symbol1] = StateS2
+	if actual, err := calc.Calculate("1101"); err != nil || actual != 1 {
+		t.Errorf("Calculate(\"1101\"): got (%d, %v), want 1", actual, err)
+	}
+	if fresh := GetModThreeConfig(); !reflect.DeepEqual(fresh.Transitions[StateS0], map[string]string{Symbol0: StateS0, Symbol1: StateS1}) {
+		t.Errorf("GetModThreeConfig should return a fresh table, got %v", fresh.Transitions[StateS0])
+	}
+}
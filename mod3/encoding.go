@@ -0,0 +1,178 @@
+package mod3
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"modulo_three_advanced/fsm"
+)
+
+// ErrInvalidWidth is returned (wrapped) by Calculate when a two's-complement
+// input does not have exactly the configured number of bits.
+var ErrInvalidWidth = errors.New("FSM input width does not match the encoding")
+
+// Encoding describes how a calculator interprets the sign of its binary
+// input. Whatever the encoding, the remainder returned is the mathematically
+// correct non-negative one, so -1 mod 3 is 2. Empty and whitespace-only
+// input still means 0.
+type Encoding struct {
+	kind  encodingKind
+	width int
+}
+
+type encodingKind int
+
+const (
+	unsignedKind encodingKind = iota
+	signMagnitudeKind
+	twosComplementKind
+)
+
+var (
+	// Unsigned treats every input as a non-negative binary number. It is the default.
+	Unsigned = Encoding{}
+	// SignMagnitude accepts an optional leading '-' before the binary magnitude.
+	SignMagnitude = Encoding{kind: signMagnitudeKind}
+)
+
+// TwosComplement reads inputs of exactly width bits as two's-complement
+// words: a leading '1' bit means the value is negative.
+func TwosComplement(width int) Encoding {
+	return Encoding{kind: twosComplementKind, width: width}
+}
+
+func (e Encoding) String() string {
+	switch e.kind {
+	case signMagnitudeKind:
+		return "sign-magnitude"
+	case twosComplementKind:
+		return fmt.Sprintf("two's-complement(%d)", e.width)
+	default:
+		return "unsigned"
+	}
+}
+
+func (e Encoding) validate() error {
+	if e.kind == twosComplementKind && e.width < 1 {
+		return fmt.Errorf("invalid encoding %s: width must be at least 1", e)
+	}
+	return nil
+}
+
+// inputFormat gathers the options describing how Calculate reads its input.
+type inputFormat struct {
+	encoding      Encoding
+	order         BitOrder
+	normalization Normalization
+}
+
+func (f inputFormat) validate() error {
+	if err := f.normalization.validate(); err != nil {
+		return err
+	}
+	return f.encoding.validate()
+}
+
+// wrap returns calculate extended to the input format: the notation is
+// normalized and the sign decoded here, the bits are run unsigned, and the
+// remainder is then corrected for the sign.
+func (f inputFormat) wrap(calculate func(string) (int, string, error)) func(string) (int, string, error) {
+	return wrapFormat(f, calculate, -1, func(remainder int) int { return f.encoding.negative(remainder, 3) })
+}
+
+// wrapFormat is wrap for any remainder type R: invalid is returned on
+// failure and negate corrects the remainder of the bits of a negative input.
+func wrapFormat[R any](f inputFormat, calculate func(string) (R, string, error), invalid R, negate func(R) R) func(string) (R, string, error) {
+	e := f.encoding
+	if e.kind == unsignedKind && f.normalization == (Normalization{}) {
+		return calculate
+	}
+	return func(input string) (R, string, error) {
+		// 1. Reduce the notation to plain bits, keeping any '-' sign.
+		text, err := f.normalization.apply(input, e.kind == signMagnitudeKind, f.order)
+		if err != nil {
+			return invalid, "", err
+		}
+
+		// Handle empty string case (value 0, remainder 0)
+		if e.kind == unsignedKind || strings.TrimSpace(text) == "" {
+			remainder, finalState, err := calculate(text)
+			return remainder, finalState, f.locate(err, input, 0)
+		}
+
+		// 2. Split off the sign.
+		bits, negative, err := e.decode(text, f.order)
+		if err != nil {
+			return invalid, "", err
+		}
+
+		// 3. Run the bits as an unsigned number.
+		remainder, finalState, err := calculate(bits)
+		if err != nil {
+			return remainder, finalState, f.locate(err, input, len(text)-len(bits))
+		}
+		if !negative {
+			return remainder, finalState, nil
+		}
+
+		// 4. Correct for the sign.
+		return negate(remainder), finalState, nil
+	}
+}
+
+// locate rewrites the Position of an *fsm.AbortError from running the bits
+// found at offset in the normalized text as a byte offset in input, where
+// the caller can find it. Other errors are returned unchanged.
+func (f inputFormat) locate(err error, input string, offset int) error {
+	var abortErr *fsm.AbortError
+	if errors.As(err, &abortErr) {
+		abortErr.Position = f.normalization.position(input, offset+abortErr.Position, f.encoding.kind == signMagnitudeKind, f.order)
+	}
+	return err
+}
+
+// negative maps the unsigned remainder modulo modulus of the bits of a
+// negative input to the remainder of the value they encode.
+func (e Encoding) negative(remainder, modulus int) int {
+	if e.kind == twosComplementKind {
+		// The word's value is N - 2^width; for modulus 3, 2^width mod 3 is 1 for even widths and 2 for odd ones.
+		return (remainder - powerOfTwoMod(e.width, modulus) + modulus) % modulus
+	}
+	// -N mod m = (m - N mod m) mod m
+	return (modulus - remainder) % modulus
+}
+
+// decode returns the bits to run unsigned and whether the value is negative.
+// The sign bit of a two's-complement word is its most significant bit, which
+// comes last in LSB-first order; a '-' sign always comes first.
+func (e Encoding) decode(input string, order BitOrder) (string, bool, error) {
+	if e.kind == signMagnitudeKind {
+		magnitude, negative := strings.CutPrefix(input, "-")
+		if negative && strings.TrimSpace(magnitude) == "" {
+			return "", false, fmt.Errorf("%w: %s", ErrInvalidInput, input)
+		}
+		return magnitude, negative, nil
+	}
+
+	if len(input) != e.width {
+		return "", false, fmt.Errorf("%w: got %d bits, want %d", ErrInvalidWidth, len(input), e.width)
+	}
+	signBit := input[0]
+	if order == LSBFirst {
+		signBit = input[len(input)-1]
+	}
+	return input, signBit == '1', nil
+}
+
+// powerOfTwoMod returns 2^n mod modulus.
+func powerOfTwoMod(n, modulus int) int {
+	result, base := 1%modulus, 2%modulus
+	for ; n > 0; n >>= 1 {
+		if n&1 == 1 {
+			result = result * base % modulus
+		}
+		base = base * base % modulus
+	}
+	return result
+}
@@ -0,0 +1,132 @@
+package mod3
+
+import (
+	"errors"
+	"math/big"
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+// -----------------------------------------------------------------------------
+// UNIT TESTS FOR WithEncoding
+// -----------------------------------------------------------------------------
+
+// calculatorFamily builds every calculator implementation with opts.
+func calculatorFamily(t *testing.T, opts ...Option) map[string]ModuloCalculator {
+	t.Helper()
+	interpreted, err := NewModThreeCalculator(GetModThreeConfig(), opts...)
+	if err != nil {
+		t.Fatalf("NewModThreeCalculator failed: %v", err)
+	}
+	typedCalc, err := NewRemainderCalculator(opts...)
+	if err != nil {
+		t.Fatalf("NewRemainderCalculator failed: %v", err)
+	}
+	generated, err := NewGeneratedModThreeCalculator(opts...)
+	if err != nil {
+		t.Fatalf("NewGeneratedModThreeCalculator failed: %v", err)
+	}
+	return map[string]ModuloCalculator{"interpreted": interpreted, "typed": typedCalc, "generated": generated}
+}
+
+func TestEncodings(t *testing.T) {
+	tests := []struct {
+		encoding Encoding
+		input    string
+		expected int
+		wantErr  error
+	}{
+		{Unsigned, "1101", 1, nil},
+		{Unsigned, "-1101", -1, ErrInvalidInput},
+		{SignMagnitude, "1101", 1, nil},
+		{SignMagnitude, "-1101", 2, nil}, // -13 mod 3 = 2
+		{SignMagnitude, "-1", 2, nil},
+		{SignMagnitude, "-11", 0, nil},
+		{SignMagnitude, "-0", 0, nil},
+		{SignMagnitude, "", 0, nil},
+		{SignMagnitude, "-", -1, ErrInvalidInput},
+		{SignMagnitude, "--1", -1, ErrInvalidInput},
+		{SignMagnitude, "1-1", -1, ErrInvalidInput},
+		{TwosComplement(4), "0101", 2, nil},     // 5
+		{TwosComplement(4), "1111", 2, nil},     // -1
+		{TwosComplement(4), "1011", 1, nil},     // -5
+		{TwosComplement(3), "111", 2, nil},      // -1
+		{TwosComplement(3), "100", 2, nil},      // -4
+		{TwosComplement(8), "11111101", 0, nil}, // -3
+		{TwosComplement(4), "", 0, nil},
+		{TwosComplement(4), "101", -1, ErrInvalidWidth},
+		{TwosComplement(4), "10101", -1, ErrInvalidWidth},
+		{TwosComplement(4), "1x01", -1, ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		for name, calc := range calculatorFamily(t, WithEncoding(tt.encoding)) {
+			actual, err := calc.Calculate(tt.input)
+			if actual != tt.expected || !errors.Is(err, tt.wantErr) {
+				t.Errorf("%s %v Calculate(%q): got (%d, %v), want (%d, %v)", name, tt.encoding, tt.input, actual, err, tt.expected, tt.wantErr)
+			}
+		}
+	}
+}
+
+func TestEncodings_InvalidWidth(t *testing.T) {
+	if _, err := NewModThreeCalculator(GetModThreeConfig(), WithEncoding(TwosComplement(0))); err == nil {
+		t.Error("NewModThreeCalculator: expected an error for width 0")
+	}
+	if _, err := NewRemainderCalculator(WithEncoding(TwosComplement(-2))); err == nil {
+		t.Error("NewRemainderCalculator: expected an error for width -2")
+	}
+	if _, err := NewGeneratedModThreeCalculator(WithEncoding(TwosComplement(0))); err == nil {
+		t.Error("NewGeneratedModThreeCalculator: expected an error for width 0")
+	}
+	for encoding, want := range map[Encoding]string{Unsigned: "unsigned", SignMagnitude: "sign-magnitude", TwosComplement(16): "two's-complement(16)"} {
+		if encoding.String() != want {
+			t.Errorf("String(): got %q, want %q", encoding.String(), want)
+		}
+	}
+}
+
+func randomBits(rng *rand.Rand, n int) string {
+	var sb strings.Builder
+	for range n {
+		sb.WriteByte("01"[rng.IntN(2)])
+	}
+	return sb.String()
+}
+
+func TestProperty_SignedEncodingsMatchBigInt(t *testing.T) {
+	rng := rand.New(rand.NewPCG(6, 3))
+	signed := calculatorFamily(t, WithEncoding(SignMagnitude))
+
+	for range 300 {
+		magnitude := randomBits(rng, 1+rng.IntN(200))
+		n, _ := new(big.Int).SetString(magnitude, 2)
+		input := magnitude
+		if rng.IntN(2) == 0 {
+			input = "-" + magnitude
+			n.Neg(n)
+		}
+		for name, calc := range signed {
+			if actual, err := calc.Calculate(input); err != nil || actual != bigMod(n, 3) {
+				t.Fatalf("%s Calculate(%q): got (%d, %v), want %d", name, input, actual, err, bigMod(n, 3))
+			}
+		}
+	}
+
+	for _, width := range []int{1, 2, 7, 8, 63, 64, 65, 200} {
+		words := calculatorFamily(t, WithEncoding(TwosComplement(width)))
+		for range 50 {
+			input := randomBits(rng, width)
+			n, _ := new(big.Int).SetString(input, 2)
+			if input[0] == '1' {
+				n.Sub(n, new(big.Int).Lsh(big.NewInt(1), uint(width)))
+			}
+			for name, calc := range words {
+				if actual, err := calc.Calculate(input); err != nil || actual != bigMod(n, 3) {
+					t.Fatalf("%s width %d Calculate(%q): got (%d, %v), want %d (%s)", name, width, input, actual, err, bigMod(n, 3), n)
+				}
+			}
+		}
+	}
+}
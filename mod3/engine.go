@@ -0,0 +1,254 @@
+package mod3
+
+import (
+	"context"
+	"iter"
+	"strconv"
+	"unicode/utf8"
+
+	"modulo_three_advanced/fsm"
+	"modulo_three_advanced/fsm/typed"
+)
+
+// engine runs a calculator's bits. automatonEngine runs the automaton a
+// ModThreeFSMConfig describes; remainderEngine runs a typed automaton whose
+// states are remainders. Both report a character outside the alphabet as an
+// *fsm.RunError of kind fsm.KindInvalidSymbol.
+type engine interface {
+	// run reads '0'/'1' text.
+	run(ctx context.Context, text string) (outcome, error)
+	// runPacked is run over packed input.
+	runPacked(p packed) (outcome, error)
+}
+
+// outcome is what a calculator needs from a run: the final state's name,
+// whether it is accepting and the remainder it stands for, -1 for none.
+type outcome struct {
+	state     string
+	accepted  bool
+	remainder int
+}
+
+// stateSpace describes what the states of a remainder automaton stand for
+// under one bit order.
+type stateSpace[S comparable] struct {
+	order     BitOrder
+	start     S              // before any bit is read
+	next      func(S, int) S // after reading a bit
+	remainder func(S) int
+	name      func(S) string
+}
+
+var (
+	// msbSpace: a state is the remainder itself; Rnew = (2 × Rold + Bit) mod 3.
+	msbSpace = stateSpace[int]{
+		order:     MSBFirst,
+		start:     0,
+		next:      func(r, bit int) int { return (2*r + bit) % 3 },
+		remainder: func(r int) int { return r },
+		name:      strconv.Itoa,
+	}
+	// lsbSpace: a state is an LSBState, the remainder and the next place value.
+	lsbSpace = stateSpace[LSBState]{
+		order:     LSBFirst,
+		start:     LSBState{Remainder: 0, Power: 1},
+		next:      LSBState.next,
+		remainder: func(s LSBState) int { return s.Remainder },
+		name:      LSBState.String,
+	}
+)
+
+// -----------------------------------------------------------------------------
+// remainderEngine: typed automata over remainders
+// -----------------------------------------------------------------------------
+
+// remainderEngine runs a typed automaton over states of type S.
+type remainderEngine[S comparable] struct {
+	fa    *typed.FiniteAutomaton[S, int]
+	space stateSpace[S]
+	table *byteTable[S] // byte-at-a-time transitions of fa; nil if they leave the known states
+}
+
+func newRemainderEngine[S comparable](fa *typed.FiniteAutomaton[S, int], states []S, space stateSpace[S]) *remainderEngine[S] {
+	table := newByteTable(states, space.order, func(s S, bit int) (S, bool) {
+		next, ok := fa.Transitions[s][bit]
+		return next, ok
+	}, fa.IsAccepting)
+	return &remainderEngine[S]{fa: fa, space: space, table: table}
+}
+
+// run parses the bits as the automaton reads them rather than copying them,
+// so memory use does not grow with the input.
+func (e *remainderEngine[S]) run(ctx context.Context, text string) (outcome, error) {
+	invalid := -1
+	result, err := e.fa.ExecuteSeqContext(ctx, bitSeq(text, &invalid))
+	if err != nil {
+		return outcome{}, err
+	}
+	if invalid >= 0 {
+		symbol, _ := utf8.DecodeRuneInString(text[invalid:])
+		return outcome{}, &fsm.RunError{Kind: fsm.KindInvalidSymbol, State: e.space.name(result.FinalState), Symbol: string(symbol), Position: invalid}
+	}
+	return e.outcome(result.FinalState, result.Accepted), nil
+}
+
+// runPacked spells the bits out for run when there is no byte table.
+func (e *remainderEngine[S]) runPacked(p packed) (outcome, error) {
+	if e.table == nil {
+		return e.run(context.Background(), p.spell(e.space.order))
+	}
+	return e.outcome(e.table.run(e.fa.InitialState, p)), nil
+}
+
+func (e *remainderEngine[S]) outcome(state S, accepted bool) outcome {
+	return outcome{state: e.space.name(state), accepted: accepted, remainder: e.space.remainder(state)}
+}
+
+// bitSeq yields the bit values of '0'/'1' text as the automaton reads them.
+// At the first other character it sets *invalid to its offset and ends the
+// sequence.
+func bitSeq(input string, invalid *int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := 0; i < len(input); i++ {
+			switch input[i] {
+			case '0':
+				if !yield(0) {
+					return
+				}
+			case '1':
+				if !yield(1) {
+					return
+				}
+			default:
+				*invalid = i
+				return
+			}
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+// automatonEngine: the configured automaton
+// -----------------------------------------------------------------------------
+
+// automatonEngine runs the automaton a ModThreeFSMConfig describes, or
+// generated code for one, and maps its final state to a remainder.
+type automatonEngine struct {
+	fa         fsm.Automaton
+	remainders map[string]int // see stateRemainders; states standing for no single remainder are missing
+	order      BitOrder
+
+	// Byte-at-a-time transitions for packed input; nil when fa's transitions
+	// are not known, in which case packed bits are spelled out.
+	table   *byteTable[string]
+	initial string
+}
+
+// newAutomatonEngine returns the engine for fa, whose transitions on bit
+// values are step.
+func newAutomatonEngine(fa fsm.Automaton, states []string, initial string, step func(state string, bit int) (string, bool), order BitOrder) *automatonEngine {
+	e := &automatonEngine{fa: fa, order: order, table: newByteTable(states, order, step, fa.IsAccepting), initial: initial}
+	if order == LSBFirst {
+		e.remainders = stateRemainders(initial, step, lsbSpace)
+	} else {
+		e.remainders = stateRemainders(initial, step, msbSpace)
+	}
+	return e
+}
+
+func (e *automatonEngine) run(ctx context.Context, text string) (outcome, error) {
+	result, err := e.execute(ctx, text)
+	if err != nil {
+		return outcome{}, err
+	}
+	return e.outcome(result.FinalState, result.Accepted), nil
+}
+
+// execute uses the engine's ExecuteContext when it has one.
+func (e *automatonEngine) execute(ctx context.Context, text string) (fsm.RunResult, error) {
+	if engine, ok := e.fa.(interface {
+		ExecuteContext(ctx context.Context, input string) (fsm.RunResult, error)
+	}); ok {
+		return engine.ExecuteContext(ctx, text)
+	}
+	if err := canceled(ctx, e.initial); err != nil {
+		return fsm.RunResult{}, err
+	}
+	return e.fa.Execute(text)
+}
+
+func (e *automatonEngine) runPacked(p packed) (outcome, error) {
+	if e.table == nil {
+		return e.run(context.Background(), p.spell(e.order))
+	}
+	return e.outcome(e.table.run(e.initial, p)), nil
+}
+
+func (e *automatonEngine) outcome(state string, accepted bool) outcome {
+	remainder, ok := e.remainders[state]
+	if !ok {
+		remainder = -1
+	}
+	return outcome{state: state, accepted: accepted, remainder: remainder}
+}
+
+// -----------------------------------------------------------------------------
+// Remainders of named states
+// -----------------------------------------------------------------------------
+
+// meanings follows the bit transitions of an automaton from initial, which
+// stands for space.start, applying space.next to every bit read. It returns
+// what each reachable state can stand for: every input ending in a state
+// leads to one of its meanings. step reports false for a missing transition.
+func meanings[S comparable](initial string, step func(state string, bit int) (string, bool), space stateSpace[S]) map[string]map[S]bool {
+	type pair struct {
+		state   string
+		meaning S
+	}
+	found := map[string]map[S]bool{initial: {space.start: true}}
+	for queue := []pair{{initial, space.start}}; len(queue) > 0; queue = queue[1:] {
+		p := queue[0]
+		for bit := range 2 {
+			to, ok := step(p.state, bit)
+			if !ok {
+				continue
+			}
+			meaning := space.next(p.meaning, bit)
+			if found[to] == nil {
+				found[to] = make(map[S]bool)
+			}
+			if !found[to][meaning] {
+				found[to][meaning] = true
+				queue = append(queue, pair{to, meaning})
+			}
+		}
+	}
+	return found
+}
+
+// stateRemainders maps each state of a bit automaton to the remainder of
+// every input ending in it, so final states can be read as remainders
+// whatever their names. States whose inputs have different remainders, or
+// that cannot be reached, are left out.
+func stateRemainders[S comparable](initial string, step func(state string, bit int) (string, bool), space stateSpace[S]) map[string]int {
+	remainders := make(map[string]int)
+	for state, ms := range meanings(initial, step, space) {
+		found := make(map[int]bool, 1)
+		for m := range ms {
+			found[space.remainder(m)] = true
+		}
+		for r := range found {
+			if len(found) == 1 {
+				remainders[state] = r
+			}
+		}
+	}
+	return remainders
+}
+
+// readsAs reports whether every reachable state of a bit automaton stands
+// for a single remainder under space, i.e. whether it reads its bits in
+// space's order.
+func readsAs[S comparable](initial string, step func(state string, bit int) (string, bool), space stateSpace[S]) bool {
+	return len(stateRemainders(initial, step, space)) == len(meanings(initial, step, space))
+}
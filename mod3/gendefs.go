@@ -0,0 +1,83 @@
+//go:build ignore
+
+// gendefs writes the definition files the generated automata are built
+// from, testdata/modthree.json and testdata/modthree_lsb.json, from
+// GetModThreeConfig and GetModThreeLSBConfig. It runs before cmd/fsmgen
+// under go generate, so the generated code always follows the configs:
+//
+//	go generate ./mod3
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"modulo_three_advanced/fsm"
+	"modulo_three_advanced/mod3"
+)
+
+func main() {
+	files := []struct {
+		name string
+		def  fsm.Definition
+	}{
+		{"modthree.json", mod3.GetModThreeConfig().Definition("mod3")},
+		{"modthree_lsb.json", mod3.GetModThreeLSBConfig().Definition("mod3-lsb")},
+	}
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join("testdata", f.name), format(f.def), 0o644); err != nil {
+			fmt.Fprintln(os.Stderr, "gendefs:", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// format encodes def with one line per field and per transition row, in the
+// order of States and Alphabet, so the files stay readable and diff well.
+func format(def fsm.Definition) []byte {
+	var b bytes.Buffer
+	b.WriteString("{\n")
+	fmt.Fprintf(&b, "  \"name\": %s,\n", encode(def.Name))
+	fmt.Fprintf(&b, "  \"states\": %s,\n", list(def.States))
+	fmt.Fprintf(&b, "  \"alphabet\": %s,\n", list(def.Alphabet))
+	fmt.Fprintf(&b, "  \"initialState\": %s,\n", encode(def.InitialState))
+	fmt.Fprintf(&b, "  \"acceptingStates\": %s,\n", list(def.AcceptingStates))
+	b.WriteString("  \"transitions\": {\n")
+	for i, state := range def.States {
+		b.WriteString("    " + encode(state) + ": {")
+		for j, symbol := range def.Alphabet {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(encode(symbol) + ": " + encode(def.Transitions[state][symbol]))
+		}
+		b.WriteString("}")
+		if i < len(def.States)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("  }\n}\n")
+	return b.Bytes()
+}
+
+func list(items []string) string {
+	var b bytes.Buffer
+	b.WriteString("[")
+	for i, item := range items {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(encode(item))
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+func encode(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
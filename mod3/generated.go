@@ -0,0 +1,40 @@
+package mod3
+
+import "modulo_three_advanced/fsm"
+
+//go:generate go run gendefs.go
+//go:generate go run ../cmd/fsmgen -def testdata/modthree.json -type generatedModThree -out modthree_gen.go
+//go:generate go run ../cmd/fsmgen -def testdata/modthree_lsb.json -type generatedModThreeLSB -out modthree_lsb_gen.go
+
+// NewGeneratedModThreeCalculator returns a calculator on generatedModThree,
+// the code-generated form of GetModThreeConfig (see modthree_gen.go), or on
+// generatedModThreeLSB under WithBitOrder(LSBFirst). Both
+// replace map lookups with an array-based transition table. Options apply
+// as for NewModThreeCalculator, except WithFSMOptions, which has no effect.
+// The code is generated from testdata/modthree.json and modthree_lsb.json,
+// which gendefs.go writes from GetModThreeConfig and GetModThreeLSBConfig;
+// run go generate ./mod3 after changing either config.
+func NewGeneratedModThreeCalculator(opts ...Option) (ModuloCalculator, error) {
+	o := applyOptions(opts)
+	if err := o.format.validate(); err != nil {
+		return nil, err
+	}
+	e := generatedEngine(generatedModThree{}, generatedModThreeStates[:], generatedModThreeDelta[:], generatedModThreeSymbol, generatedModThreeInitial, o.format.order)
+	if o.format.order == LSBFirst {
+		e = generatedEngine(generatedModThreeLSB{}, generatedModThreeLSBStates[:], generatedModThreeLSBDelta[:], generatedModThreeLSBSymbol, generatedModThreeLSBInitial, o.format.order)
+	}
+	return &ModThreeCalculator{newCalculator(e, o)}, nil
+}
+
+// generatedEngine returns the engine for a generated automaton, with the
+// byte table and remainders worked out from its arrays.
+func generatedEngine(fa fsm.Automaton, states []string, delta [][2]int, symbol func(rune) int, initial int, order BitOrder) *automatonEngine {
+	index := make(map[string]int, len(states))
+	for i, s := range states {
+		index[s] = i
+	}
+	step := func(state string, bit int) (string, bool) {
+		return states[delta[index[state]][symbol(rune('0'+bit))]], true
+	}
+	return newAutomatonEngine(fa, states, states[initial], step, order)
+}
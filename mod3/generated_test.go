@@ -0,0 +1,124 @@
+package mod3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"modulo_three_advanced/fsm"
+	"modulo_three_advanced/fsm/fsmtest"
+)
+
+// -----------------------------------------------------------------------------
+// UNIT TESTS FOR the code-generated calculator
+// -----------------------------------------------------------------------------
+
+func TestGeneratedModThreeCalculator(t *testing.T) {
+	calc, err := NewGeneratedModThreeCalculator()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if remainder, err := calc.Calculate("1101"); err != nil || remainder != 1 {
+		t.Errorf("Calculate(1101): got (%d, %v), want 1", remainder, err)
+	}
+	if _, err := calc.Calculate("10a1"); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("Expected ErrInvalidInput, got %v", err)
+	}
+	var runErr *fsm.RunError
+	if _, err := (generatedModThree{}).Run("12"); !errors.As(err, &runErr) || runErr.Position != 1 {
+		t.Errorf("Expected a RunError at position 1, got %v", err)
+	}
+}
+
+// TestGeneratedModThreeCalculator_CanceledMidRun checks that the generated
+// engine keeps checking ctx while it runs, not only before it starts.
+func TestGeneratedModThreeCalculator_CanceledMidRun(t *testing.T) {
+	calc, err := NewGeneratedModThreeCalculator()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Far more bits than can be read before the deadline.
+	input := strings.Repeat("1", 1<<24)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	_, err = calc.(ContextCalculator).CalculateContext(ctx, input)
+	var abortErr *fsm.AbortError
+	if !errors.As(err, &abortErr) || abortErr.Kind != fsm.KindCanceled || abortErr.Steps == 0 || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected a run stopped by the deadline, got %v", err)
+	}
+}
+
+// TestGeneratedDefinitions checks that the definition files the generated
+// code is built from describe the same automata as the config functions,
+// i.e. that go generate ./mod3 was run after the configs last changed.
+func TestGeneratedDefinitions(t *testing.T) {
+	tests := []struct {
+		file     string
+		expected fsm.Definition
+	}{
+		{"modthree.json", GetModThreeConfig().Definition("mod3")},
+		{"modthree_lsb.json", GetModThreeLSBConfig().Definition("mod3-lsb")},
+	}
+
+	for _, tt := range tests {
+		def, err := fsm.LoadDefinition(filepath.Join("testdata", tt.file))
+		if err != nil {
+			t.Fatalf("LoadDefinition(%s) failed: %v", tt.file, err)
+		}
+		if !reflect.DeepEqual(def, tt.expected) {
+			t.Errorf("%s is out of step with its config function:\n got %+v\nwant %+v", tt.file, def, tt.expected)
+		}
+	}
+}
+
+// TestGeneratedModThreeCalculator_Packed checks that text input runs on the
+// generated automaton and packed input on the byte table built from its arrays.
+func TestGeneratedModThreeCalculator_Packed(t *testing.T) {
+	for _, order := range []BitOrder{MSBFirst, LSBFirst} {
+		calc, err := NewGeneratedModThreeCalculator(WithBitOrder(order))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		generated := calc.(*ModThreeCalculator)
+		e, ok := generated.engine.(*automatonEngine)
+		if !ok || e.table == nil {
+			t.Fatalf("Order %v: expected the generated automaton with a byte table, got engine %T", order, generated.engine)
+		}
+		switch e.fa.(type) {
+		case generatedModThree, generatedModThreeLSB:
+		default:
+			t.Fatalf("Order %v: expected a generated automaton, got %T", order, e.fa)
+		}
+		// Read as 0xB401 = 46081 under MSBFirst and as 0x01B4 = 436 under LSBFirst.
+		data := []byte{0xB4, 0x01}
+		want := 1
+		if remainder, err := generated.CalculateBytes(data, 16); err != nil || remainder != want {
+			t.Errorf("Order %v: CalculateBytes(%x): got (%d, %v), want %d", order, data, remainder, err, want)
+		}
+	}
+}
+
+func TestProperty_GeneratedMatchesInterpreted(t *testing.T) {
+	_, calc, g := setupPropertyTest(t, 5)
+	g.InvalidRate = 0.2
+	generated, err := NewGeneratedModThreeCalculator()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	fsmtest.ForAll(t, g, func(input string) error {
+		want, wantErr := calc.Calculate(input)
+		got, err := generated.Calculate(input)
+		if got != want || (err != nil) != (wantErr != nil) {
+			return fmt.Errorf("generated = (%d, %v), interpreted = (%d, %v)", got, err, want, wantErr)
+		}
+		return nil
+	})
+}
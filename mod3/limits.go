@@ -0,0 +1,77 @@
+package mod3
+
+import (
+	"context"
+
+	"modulo_three_advanced/fsm"
+)
+
+// limits bounds the work of a single calculation (WithMaxInputLength and
+// WithMaxSteps). Stops are reported as *fsm.AbortError, as by the engine.
+// Each bit is one step, so the step limit is applied by running only the
+// first maxSteps bits, which costs nothing in the transition loop.
+type limits struct {
+	maxSteps       int // 0 means no limit
+	maxInputLength int // in bytes; 0 means no limit
+}
+
+// checkInput rejects input over the length limit before it is read.
+func (l limits) checkInput(length int) error {
+	if l.maxInputLength > 0 && length > l.maxInputLength {
+		return &fsm.AbortError{Kind: fsm.KindInputTooLong, Limit: l.maxInputLength}
+	}
+	return nil
+}
+
+// clip returns the number of bits to run out of n, and whether that cuts
+// the input short.
+func (l limits) clip(n int) (int, bool) {
+	if l.maxSteps > 0 && n > l.maxSteps {
+		return l.maxSteps, true
+	}
+	return n, false
+}
+
+// clipText is clip for text input: it returns the length in bytes of the
+// first maxSteps characters, so a multi-byte character is never split.
+func (l limits) clipText(input string) (int, bool) {
+	if l.maxSteps <= 0 || len(input) <= l.maxSteps {
+		return len(input), false
+	}
+	steps := 0
+	for i := range input {
+		if steps == l.maxSteps {
+			return i, true
+		}
+		steps++
+	}
+	return len(input), false
+}
+
+// stepLimit reports a run cut short after maxSteps bits in state, with the
+// first unread bit at position.
+func (l limits) stepLimit(state string, position int) error {
+	return &fsm.AbortError{Kind: fsm.KindStepLimit, State: state, Steps: l.maxSteps, Position: position, Limit: l.maxSteps}
+}
+
+// runPacked runs p, or only its first maxSteps bits when it is longer.
+func (l limits) runPacked(p packed, run func(packed) (int, string, error)) (int, string, error) {
+	n, clipped := l.clip(p.bitLength)
+	if !clipped {
+		return run(p)
+	}
+	p.bitLength = n
+	_, finalState, err := run(p)
+	if err != nil {
+		return -1, finalState, err
+	}
+	return -1, finalState, l.stepLimit(finalState, n)
+}
+
+// canceled reports ctx's error as an engine would, for engines without RunContext.
+func canceled(ctx context.Context, state string) error {
+	if err := ctx.Err(); err != nil {
+		return &fsm.AbortError{Kind: fsm.KindCanceled, State: state, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package mod3
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"modulo_three_advanced/fsm"
+)
+
+// -----------------------------------------------------------------------------
+// UNIT TESTS FOR CalculateContext, WithMaxSteps and WithMaxInputLength
+// -----------------------------------------------------------------------------
+
+func TestWithMaxSteps(t *testing.T) {
+	for name, calc := range calculatorFamily(t, WithMaxSteps(4)) {
+		if actual, err := calc.Calculate("1101"); err != nil || actual != 1 {
+			t.Errorf("%s Calculate(\"1101\"): got (%d, %v), want 1 within the limit", name, actual, err)
+		}
+
+		// 11011 stops after 1101, in the state for remainder 1.
+		actual, err := calc.Calculate("11011")
+		var abortErr *fsm.AbortError
+		if actual != -1 || !errors.As(err, &abortErr) || abortErr.Kind != fsm.KindStepLimit || abortErr.Steps != 4 || !errors.Is(err, fsm.ErrStepLimit) {
+			t.Errorf("%s Calculate(\"11011\"): got (%d, %v), want a step-limit stop", name, actual, err)
+			continue
+		}
+		if abortErr.State != StateS1 && abortErr.State != "1" {
+			t.Errorf("%s: stopped in %q, want the remainder-1 state", name, abortErr.State)
+		}
+
+		// Bits past the limit are never read, even invalid ones.
+		if _, err := calc.Calculate("11011x"); !errors.Is(err, fsm.ErrStepLimit) {
+			t.Errorf("%s Calculate(\"11011x\"): got %v, want the step limit", name, err)
+		}
+		if _, err := calc.Calculate("1x011"); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("%s Calculate(\"1x011\"): got %v, want ErrInvalidInput", name, err)
+		}
+
+		packed := calc.(PackedCalculator)
+		if _, err := packed.CalculateBytes([]byte{0xd8}, 5); !errors.As(err, &abortErr) || abortErr.Steps != 4 {
+			t.Errorf("%s CalculateBytes: got %v, want a step-limit stop", name, err)
+		}
+		// A big.Int is read in whole bytes, leading zeros included.
+		if _, err := packed.CalculateBig(big.NewInt(13)); !errors.Is(err, fsm.ErrStepLimit) {
+			t.Errorf("%s CalculateBig(13): got %v, want the step limit", name, err)
+		}
+	}
+}
+
+func TestWithMaxSteps_CountsNormalizedBits(t *testing.T) {
+	for name, calc := range calculatorFamily(t, WithMaxSteps(4), WithNormalization(LenientInput)) {
+		if actual, err := calc.Calculate("0b1101\n"); err != nil || actual != 1 {
+			t.Errorf("%s Calculate(\"0b1101\\n\"): got (%d, %v), want 1 within the limit", name, actual, err)
+		}
+		if _, err := calc.Calculate("0x1b"); !errors.Is(err, fsm.ErrStepLimit) {
+			t.Errorf("%s Calculate(\"0x1b\"): got %v, want the step limit", name, err)
+		}
+	}
+}
+
+func TestWithMaxSteps_ReportsInputPositions(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		input    string
+		position int // of the first unread bit in input
+	}{
+		{"Plain", nil, "11011", 4},
+		{"Prefix and separator", []Option{WithNormalization(LenientInput)}, "0b11_011", 7},
+		{"Hexadecimal digit", []Option{WithNormalization(LenientInput)}, "0x1b", 3},
+		{"Sign", []Option{WithEncoding(SignMagnitude)}, "-11011", 5},
+		{"Sign and prefix", []Option{WithEncoding(SignMagnitude), WithNormalization(LenientInput)}, "-0b1101_1", 8},
+	}
+
+	for _, tt := range tests {
+		for name, calc := range calculatorFamily(t, append(tt.opts, WithMaxSteps(4))...) {
+			_, err := calc.Calculate(tt.input)
+			var abortErr *fsm.AbortError
+			if !errors.As(err, &abortErr) || abortErr.Steps != 4 || abortErr.Position != tt.position {
+				t.Errorf("%s/%s Calculate(%q): got %v (%+v), want a step-limit stop at position %d", tt.name, name, tt.input, err, abortErr, tt.position)
+			}
+		}
+	}
+}
+
+func TestLimits_ClipText(t *testing.T) {
+	tests := []struct {
+		maxSteps int
+		input    string
+		expected int
+		clipped  bool
+	}{
+		{0, "11011", 5, false},
+		{5, "11011", 5, false},
+		{4, "11011", 4, true},
+		{2, "1é1", 3, true}, // the two-byte character is kept whole
+		{2, "1é", 3, false},
+	}
+
+	for _, tt := range tests {
+		n, clipped := limits{maxSteps: tt.maxSteps}.clipText(tt.input)
+		if n != tt.expected || clipped != tt.clipped {
+			t.Errorf("clipText(%q) with %d steps: got (%d, %t), want (%d, %t)", tt.input, tt.maxSteps, n, clipped, tt.expected, tt.clipped)
+		}
+	}
+}
+
+func TestWithMaxInputLength(t *testing.T) {
+	for name, calc := range calculatorFamily(t, WithMaxInputLength(8)) {
+		if actual, err := calc.Calculate("11011011"); err != nil || actual != 0 {
+			t.Errorf("%s Calculate: got (%d, %v), want 0", name, actual, err)
+		}
+		_, err := calc.Calculate(strings.Repeat("1", 9))
+		var abortErr *fsm.AbortError
+		if !errors.As(err, &abortErr) || abortErr.Kind != fsm.KindInputTooLong || abortErr.Limit != 8 || !errors.Is(err, fsm.ErrInputTooLong) {
+			t.Errorf("%s: got %v, want input too long", name, err)
+		}
+		if _, err := calc.(PackedCalculator).CalculateWords([]uint64{1, 2}, 64); !errors.Is(err, fsm.ErrInputTooLong) {
+			t.Errorf("%s CalculateWords: got %v, want input too long", name, err)
+		}
+	}
+}
+
+func TestCalculateContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for name, calc := range calculatorFamily(t) {
+		withContext := calc.(ContextCalculator)
+		if actual, err := withContext.CalculateContext(context.Background(), "1101"); err != nil || actual != 1 {
+			t.Errorf("%s CalculateContext: got (%d, %v), want 1", name, actual, err)
+		}
+		actual, err := withContext.CalculateContext(ctx, "1101")
+		var abortErr *fsm.AbortError
+		if actual != -1 || !errors.As(err, &abortErr) || abortErr.Kind != fsm.KindCanceled || !errors.Is(err, context.Canceled) {
+			t.Errorf("%s CalculateContext(canceled): got (%d, %v)", name, actual, err)
+		}
+		// Empty input needs no run, so it succeeds whatever the context.
+		if actual, err := withContext.CalculateContext(ctx, ""); err != nil || actual != 0 {
+			t.Errorf("%s CalculateContext(canceled, \"\"): got (%d, %v), want 0", name, actual, err)
+		}
+	}
+
+	multi, err := NewMultiModCalculator([]int{3, 5}, WithMaxSteps(2))
+	if err != nil {
+		t.Fatalf("NewMultiModCalculator failed: %v", err)
+	}
+	if _, err := multi.RemaindersContext(ctx, "11"); !errors.Is(err, context.Canceled) {
+		t.Errorf("RemaindersContext(canceled): got %v", err)
+	}
+	if _, err := multi.Remainders("111"); !errors.Is(err, fsm.ErrStepLimit) {
+		t.Errorf("Remainders over the step limit: got %v", err)
+	}
+}
@@ -1,8 +1,14 @@
 package mod3
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"math/big"
 	"modulo_three_advanced/fsm"
+	"modulo_three_advanced/tracing"
+	"strconv"
 	"strings"
 )
 
@@ -17,12 +23,36 @@ const (
 	Symbol1 = "1"
 )
 
+// Sentinel errors returned (wrapped) by Calculate; match them with errors.Is.
+var (
+	ErrInvalidInput = errors.New("FSM execution ended in validate Input")
+	ErrNonAccepting = errors.New("FSM execution ended in non-accepting state")
+	ErrUnknownState = errors.New("FSM execution resulted in unknown state")
+)
+
+// ModuloCalculator computes the remainder of a binary input. Every
+// implementation in this package is immutable once constructed, so one
+// calculator may serve Calculate calls from many goroutines at once.
 type ModuloCalculator interface {
 	Calculate(input string) (remainder int, err error)
 }
 
+// ContextCalculator is implemented by the calculators in this package, whose
+// calculations stop early with an *fsm.AbortError when ctx is done.
+type ContextCalculator interface {
+	ModuloCalculator
+	CalculateContext(ctx context.Context, input string) (remainder int, err error)
+}
+
+var (
+	_ ContextCalculator = (*ModThreeCalculator)(nil)
+	_ ContextCalculator = (*RemainderCalculator)(nil)
+)
+
+// ModThreeCalculator runs the automaton a ModThreeFSMConfig describes and
+// reads its final state as a remainder.
 type ModThreeCalculator struct {
-	fa fsm.Automaton // The underlying generic FSM engine.
+	*calculator
 }
 
 type ModThreeFSMConfig struct {
@@ -50,10 +80,41 @@ func GetModThreeConfig() ModThreeFSMConfig {
 	}
 }
 
+// Definition returns cfg in the serialisable fsm form, e.g. for fsm.Analyze
+// or code generation.
+func (cfg ModThreeFSMConfig) Definition(name string) fsm.Definition {
+	return fsm.Definition{
+		Name:            name,
+		States:          cfg.States,
+		Alphabet:        cfg.Alphabet,
+		InitialState:    cfg.InitialState,
+		AcceptingStates: cfg.AcceptingStates,
+		Transitions:     cfg.Transitions,
+	}
+}
+
 // NewModThreeCalculator initializes the calculator using the separated configuration.
-func NewModThreeCalculator(cfg ModThreeFSMConfig) (ModuloCalculator, error) {
+// Options such as WithLogger are optional; the default calculator is silent.
+//
+// cfg may name its states freely: the remainder each state stands for is
+// worked out by following the transitions from the initial state. A final
+// state reached by inputs with different remainders is reported as
+// ErrUnknownState.
+//
+// Under WithBitOrder(LSBFirst), a cfg that only works most significant bit
+// first, such as GetModThreeConfig, is replaced by GetModThreeLSBConfig, so
+// the option works on its own.
+func NewModThreeCalculator(cfg ModThreeFSMConfig, opts ...Option) (ModuloCalculator, error) {
+	o := applyOptions(opts)
+	if err := o.format.validate(); err != nil {
+		return nil, err
+	}
+	if o.format.order == LSBFirst && readsOnlyMSBFirst(cfg) {
+		cfg = GetModThreeLSBConfig()
+	}
+
 	// Pass the structured configuration data to the FSM constructor
-	fa, err := fsm.NewFiniteAutomaton(cfg.States, cfg.Alphabet, cfg.InitialState, cfg.AcceptingStates, cfg.Transitions)
+	fa, err := buildAutomaton(cfg, o.fsmOptions...)
 
 	// This is the error path you wanted to ensure is covered.
 	if err != nil {
@@ -61,62 +122,176 @@ func NewModThreeCalculator(cfg ModThreeFSMConfig) (ModuloCalculator, error) {
 		return nil, fmt.Errorf("failed to initialize FSM engine: %w", err)
 	}
 
-	return &ModThreeCalculator{fa: fa}, nil
+	// The engine is frozen, so cfg's maps may change later without affecting
+	// the calculator and concurrent Calculate calls are safe.
+	sealed := fa.Freeze()
+	e := newAutomatonEngine(sealed, cfg.States, fa.InitialState, transitionOnBit(sealed), o.format.order)
+	return &ModThreeCalculator{newCalculator(e, o)}, nil
+}
+
+// readsOnlyMSBFirst reports whether cfg computes remainders when its bits are
+// read most significant first but not when they are read least significant
+// first.
+func readsOnlyMSBFirst(cfg ModThreeFSMConfig) bool {
+	step := func(state string, bit int) (string, bool) {
+		next, ok := cfg.Transitions[state][strconv.Itoa(bit)]
+		return next, ok
+	}
+	return readsAs(cfg.InitialState, step, msbSpace) && !readsAs(cfg.InitialState, step, lsbSpace)
+}
+
+// BucketSizes reports how many bitLength-bit inputs (leading zeros included)
+// land in each remainder bucket. The result is indexed by remainder and the
+// buckets sum to 2^bitLength; a negative bitLength has no inputs and all
+// buckets are zero.
+func BucketSizes(bitLength int) ([]*big.Int, error) {
+	fa, err := buildAutomaton(GetModThreeConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize FSM engine: %w", err)
+	}
+	remainders := stateRemainders(fa.InitialState, transitionOnBit(fa.Freeze()), msbSpace)
+
+	sizes := []*big.Int{new(big.Int), new(big.Int), new(big.Int)}
+	for state, count := range fsm.CountByState(fa, bitLength) {
+		if remainder, ok := remainders[state]; ok {
+			sizes[remainder].Add(sizes[remainder], count)
+		}
+	}
+	return sizes, nil
+}
+
+// buildAutomaton constructs the concrete FSM engine described by cfg.
+func buildAutomaton(cfg ModThreeFSMConfig, opts ...fsm.Option) (*fsm.FiniteAutomaton, error) {
+	fa, err := fsm.NewFiniteAutomaton(cfg.States, cfg.Alphabet, cfg.InitialState, cfg.AcceptingStates, cfg.Transitions, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return fa.(*fsm.FiniteAutomaton), nil
 }
 
 // --- PRIVATE HELPER METHODS ---
 
-// stateToRemainder maps the final state to the required remainder (0, 1, or 2).
-func (c *ModThreeCalculator) stateToRemainder(state string) int {
-	switch state {
-	case StateS0:
-		return 0
-	case StateS1:
-		return 1
-	case StateS2:
-		return 2
-	default:
-		// Should not happen with valid FSM execution
-		return -1
+// transitionOnBit adapts δ to bit values for newByteTable and stateRemainders.
+func transitionOnBit(fa *fsm.Sealed) func(state string, bit int) (string, bool) {
+	symbols := [2]string{Symbol0, Symbol1}
+	return func(state string, bit int) (string, bool) {
+		return fa.Transition(state, symbols[bit])
 	}
 }
 
-// isStateAccepting checks if the final state is one of the designated accepting states.
-func (c *ModThreeCalculator) isStateAccepting(finalState string) bool {
-	return c.fa.IsAccepting(finalState)
+// -----------------------------------------------------------------------------
+// calculator: what ModThreeCalculator and RemainderCalculator share
+// -----------------------------------------------------------------------------
+
+// calculator reads input in the configured format, within the configured
+// limits, and runs its bits on engine.
+type calculator struct {
+	engine engine
+	logger *slog.Logger   // Optional; nil keeps Calculate silent.
+	tracer tracing.Tracer // Optional span instrumentation.
+
+	format inputFormat // Encoding and bit order of the input.
+	limits limits      // WithMaxSteps and WithMaxInputLength.
+}
+
+func newCalculator(e engine, o calculatorOptions) *calculator {
+	return &calculator{engine: e, logger: o.logger, tracer: o.tracer, format: o.format, limits: o.limits}
 }
 
 // --- PUBLIC INTERFACE METHOD IMPLEMENTATION ---
 
-// Calculate runs the binary input through the configured FSM and returns the final remainder.
+// Calculate runs the binary input through the automaton and returns the final remainder.
 // This implements the ModuloCalculator interface.
-func (c *ModThreeCalculator) Calculate(input string) (int, error) {
+func (c *calculator) Calculate(input string) (int, error) {
+	return c.CalculateContext(context.Background(), input)
+}
+
+// CalculateContext is Calculate that stops with an *fsm.AbortError once ctx
+// is done. Engines without ExecuteContext only check ctx before running.
+func (c *calculator) CalculateContext(ctx context.Context, input string) (int, error) {
+	return observe(ctx, c.logger, c.tracer, len(input), func(ctx context.Context) (int, string, error) {
+		if err := c.limits.checkInput(len(input)); err != nil {
+			return -1, "", err
+		}
+		return c.format.wrap(func(bits string) (int, string, error) { return c.calculate(ctx, bits) })(input)
+	})
+}
+
+// observe runs calculate with the optional logging and tracing shared by the
+// calculators in this package. inputLength is in bytes. R is int, or []int
+// for MultiModCalculator, whose remainders are recorded as text. calculate
+// receives ctx, carrying the "mod3.Calculate" span when there is one.
+func observe[R any](ctx context.Context, logger *slog.Logger, tracer tracing.Tracer, inputLength int, calculate func(ctx context.Context) (R, string, error)) (R, error) {
+	var span tracing.Span
+	if tracer != nil {
+		ctx, span = tracer.Start(ctx, "mod3.Calculate")
+		defer span.End()
+		span.SetAttributes(tracing.Int(tracing.KeyInputLength, inputLength))
+	}
+
+	remainder, finalState, err := calculate(ctx)
+	if err != nil && logger != nil {
+		logger.Warn("Mod-Three calculation failed", "input_length", inputLength, "error", err)
+	}
+	if span != nil {
+		if finalState != "" {
+			span.SetAttributes(tracing.String(tracing.KeyFinalState, finalState))
+		}
+		if err != nil {
+			span.RecordError(err)
+		} else if r, ok := any(remainder).(int); ok {
+			span.SetAttributes(tracing.Int(tracing.KeyRemainder, r))
+		} else {
+			span.SetAttributes(tracing.String(tracing.KeyRemainder, fmt.Sprint(remainder)))
+		}
+	}
+	return remainder, err
+}
+
+// calculate holds the actual computation and also returns the final state
+// (empty if the engine did not run); Calculate adds logging and tracing around it.
+func (c *calculator) calculate(ctx context.Context, input string) (int, string, error) {
 	// Handle empty string case (value 0, remainder 0)
 	if strings.TrimSpace(input) == "" {
-		return 0, nil
+		return 0, "", nil
 	}
 
-	if !c.fa.ValidateInput(input) {
-		return -1, fmt.Errorf("FSM execution ended in validate Input: %s", input)
-	}
+	// Bits past the step limit are never read.
+	n, clipped := c.limits.clipText(input)
 
-	// 1. Run the input against the generic FA engine
-	finalState, err := c.fa.Run(input)
+	// 1. Run the input against the engine
+	out, err := c.engine.run(ctx, input[:n])
 	if err != nil {
-		return -1, err
+		return -1, "", invalidInput(err, input)
 	}
+	if clipped {
+		return -1, out.state, c.limits.stepLimit(out.state, n)
+	}
+	return c.finish(out)
+}
+
+// invalidInput reports an engine error on a symbol outside the alphabet as
+// ErrInvalidInput; other errors are returned as they are.
+func invalidInput(err error, input string) error {
+	var runErr *fsm.RunError
+	if errors.As(err, &runErr) && runErr.Kind == fsm.KindInvalidSymbol {
+		return fmt.Errorf("%w: %s", ErrInvalidInput, input)
+	}
+	return err
+}
 
+// finish checks the final state and maps it to the remainder.
+func (c *calculator) finish(out outcome) (int, string, error) {
 	// 2. Acceptance Check
-	if !c.isStateAccepting(finalState) {
-		return -1, fmt.Errorf("FSM execution ended in non-accepting state: %s", finalState)
+	if !out.accepted {
+		return -1, out.state, fmt.Errorf("%w: %s", ErrNonAccepting, out.state)
 	}
 
 	// 3. Map the resulting state to the remainder output
-	remainder := c.stateToRemainder(finalState)
-	if remainder == -1 {
-		// Should only happen if finalState is totally unexpected (e.g. "S99")
-		return -1, fmt.Errorf("FSM execution resulted in unknown state: %s", finalState)
+	if out.remainder < 0 {
+		// Should only happen if the final state is totally unexpected (e.g. "S99")
+		return -1, out.state, fmt.Errorf("%w: %s", ErrUnknownState, out.state)
 	}
 
-	return remainder, nil
+	return out.remainder, out.state, nil
 }
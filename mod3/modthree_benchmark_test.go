@@ -105,4 +105,17 @@ func BenchmarkCalculate_InvalidInput(b *testing.B) {
 		// is what we are interested in.
 		_, _ = calc.Calculate(input) 
 	}
-}
\ No newline at end of file
+}
+
+// BenchmarkCalculateBytes benchmarks 1 MiB of packed input (8 Mbit) through the byte table.
+func BenchmarkCalculateBytes(b *testing.B) {
+	calc := setupCalculator(b).(PackedCalculator)
+	data := []byte(strings.Repeat("\xa5", 1<<20))
+
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r, _ := calc.CalculateBytes(data, 8*len(data))
+		result = r
+	}
+}
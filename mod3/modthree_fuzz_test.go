@@ -0,0 +1,36 @@
+package mod3
+
+import (
+	"testing"
+)
+
+// FuzzCalculate compares ModThreeCalculator.Calculate with math/big on
+// arbitrary strings. Seed corpora live in testdata/fuzz/FuzzCalculate;
+// run with `go test -fuzz=FuzzCalculate ./mod3`.
+func FuzzCalculate(f *testing.F) {
+	calc, err := NewModThreeCalculator(GetModThreeConfig())
+	if err != nil {
+		f.Fatalf("Failed to initialize calculator: %v", err)
+	}
+	for _, seed := range []string{"", "0", "1", "1101", "1A01", " ", "000101", "10101010101010101010101010101010101010101010101010101010101010101"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		actual, err := calc.Calculate(input)
+		n, oracleErr := parseBinary(input)
+
+		if (err != nil) != (oracleErr != nil) {
+			t.Fatalf("Calculate(%q) error = %v, math/big error = %v", input, err, oracleErr)
+		}
+		if err != nil {
+			if actual != -1 {
+				t.Fatalf("Calculate(%q) returned %d alongside an error, want -1", input, actual)
+			}
+			return
+		}
+		if expected := bigMod(n, 3); actual != expected {
+			t.Fatalf("Calculate(%q) = %d, math/big = %d", input, actual, expected)
+		}
+	})
+}
@@ -0,0 +1,144 @@
+// Code generated by fsmgen from modthree.json. DO NOT EDIT.
+
+package mod3
+
+import (
+	"context"
+
+	"modulo_three_advanced/fsm"
+)
+
+// generatedModThree is a generated fsm.Automaton with its transition table baked
+// into arrays. It behaves like the interpreted fsm.FiniteAutomaton built from
+// the same definition.
+type generatedModThree struct{}
+
+var _ fsm.Automaton = generatedModThree{}
+
+const generatedModThreeInitial = 0
+
+var generatedModThreeStates = [...]string{"S0", "S1", "S2"}
+
+var generatedModThreeAccepting = [...]bool{true, true, true}
+
+// generatedModThreeDelta[state][symbol] is the next state.
+var generatedModThreeDelta = [...][2]int{
+	{0, 1},
+	{2, 0},
+	{1, 2},
+}
+
+// generatedModThreeSymbol returns the index of char in the alphabet, or -1.
+func generatedModThreeSymbol(char rune) int {
+	switch char {
+	case '0':
+		return 0
+	case '1':
+		return 1
+	}
+	return -1
+}
+
+// generatedModThreeCheckInterval is the number of steps between checks of a run's
+// context, as in fsm.FiniteAutomaton; it must be a power of two.
+const generatedModThreeCheckInterval = 4096
+
+// Run implements fsm.Automaton.
+func (fa generatedModThree) Run(input string) (string, error) {
+	return fa.RunContext(context.Background(), input)
+}
+
+// RunContext is Run that stops with an *fsm.AbortError of kind
+// fsm.KindCanceled once ctx is done. The context is checked every few
+// thousand symbols.
+func (generatedModThree) RunContext(ctx context.Context, input string) (string, error) {
+	done := ctx.Done() // nil for context.Background(), which is never checked
+	state := generatedModThreeInitial
+	steps := 0
+	for pos, char := range input {
+		if done != nil && steps&(generatedModThreeCheckInterval-1) == 0 {
+			select {
+			case <-done:
+				return "", &fsm.AbortError{Kind: fsm.KindCanceled, State: generatedModThreeStates[state], Steps: steps, Position: pos, Err: ctx.Err()}
+			default:
+			}
+		}
+		symbol := generatedModThreeSymbol(char)
+		if symbol < 0 {
+			return "", &fsm.RunError{Kind: fsm.KindInvalidSymbol, State: generatedModThreeStates[state], Symbol: string(char), Position: pos}
+		}
+		state = generatedModThreeDelta[state][symbol]
+		steps++
+	}
+	return generatedModThreeStates[state], nil
+}
+
+// Execute implements fsm.Automaton.
+func (fa generatedModThree) Execute(input string) (fsm.RunResult, error) {
+	return fa.ExecuteContext(context.Background(), input)
+}
+
+// ExecuteContext is Execute that stops like RunContext once ctx is done.
+func (generatedModThree) ExecuteContext(ctx context.Context, input string) (fsm.RunResult, error) {
+	done := ctx.Done()
+	var visits [len(generatedModThreeStates)]int
+	state := generatedModThreeInitial
+	visits[state]++
+	result := fsm.RunResult{FailedAt: -1}
+	var err error
+	for pos, char := range input {
+		if done != nil && result.Consumed&(generatedModThreeCheckInterval-1) == 0 {
+			select {
+			case <-done:
+				err = &fsm.AbortError{Kind: fsm.KindCanceled, State: generatedModThreeStates[state], Steps: result.Consumed, Position: pos, Err: ctx.Err()}
+			default:
+			}
+			if err != nil {
+				result.FailedAt = pos
+				break
+			}
+		}
+		symbol := generatedModThreeSymbol(char)
+		if symbol < 0 {
+			err = &fsm.RunError{Kind: fsm.KindInvalidSymbol, State: generatedModThreeStates[state], Symbol: string(char), Position: pos}
+			result.FailedAt = pos
+			break
+		}
+		state = generatedModThreeDelta[state][symbol]
+		visits[state]++
+		result.Consumed++
+	}
+
+	result.FinalState = generatedModThreeStates[state]
+	result.Visits = make(map[string]int)
+	for i, n := range visits {
+		if n > 0 {
+			result.Visits[generatedModThreeStates[i]] = n
+		}
+	}
+	if err != nil {
+		return result, err
+	}
+	result.Accepted = generatedModThreeAccepting[state]
+	return result, nil
+}
+
+// IsAccepting implements fsm.Automaton.
+func (generatedModThree) IsAccepting(state string) bool {
+	for i, name := range generatedModThreeStates {
+		if name == state {
+			return generatedModThreeAccepting[i]
+		}
+	}
+	return false
+}
+
+// ValidateInput implements fsm.Automaton.
+func (generatedModThree) ValidateInput(input string) bool {
+	for _, char := range input {
+		if generatedModThreeSymbol(char) < 0 {
+			return false
+		}
+	}
+	return true
+}
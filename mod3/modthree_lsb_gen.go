@@ -0,0 +1,147 @@
+// Code generated by fsmgen from modthree_lsb.json. DO NOT EDIT.
+
+package mod3
+
+import (
+	"context"
+
+	"modulo_three_advanced/fsm"
+)
+
+// generatedModThreeLSB is a generated fsm.Automaton with its transition table baked
+// into arrays. It behaves like the interpreted fsm.FiniteAutomaton built from
+// the same definition.
+type generatedModThreeLSB struct{}
+
+var _ fsm.Automaton = generatedModThreeLSB{}
+
+const generatedModThreeLSBInitial = 0
+
+var generatedModThreeLSBStates = [...]string{"R0P1", "R0P2", "R1P1", "R1P2", "R2P1", "R2P2"}
+
+var generatedModThreeLSBAccepting = [...]bool{true, true, true, true, true, true}
+
+// generatedModThreeLSBDelta[state][symbol] is the next state.
+var generatedModThreeLSBDelta = [...][2]int{
+	{1, 3},
+	{0, 4},
+	{3, 5},
+	{2, 0},
+	{5, 1},
+	{4, 2},
+}
+
+// generatedModThreeLSBSymbol returns the index of char in the alphabet, or -1.
+func generatedModThreeLSBSymbol(char rune) int {
+	switch char {
+	case '0':
+		return 0
+	case '1':
+		return 1
+	}
+	return -1
+}
+
+// generatedModThreeLSBCheckInterval is the number of steps between checks of a run's
+// context, as in fsm.FiniteAutomaton; it must be a power of two.
+const generatedModThreeLSBCheckInterval = 4096
+
+// Run implements fsm.Automaton.
+func (fa generatedModThreeLSB) Run(input string) (string, error) {
+	return fa.RunContext(context.Background(), input)
+}
+
+// RunContext is Run that stops with an *fsm.AbortError of kind
+// fsm.KindCanceled once ctx is done. The context is checked every few
+// thousand symbols.
+func (generatedModThreeLSB) RunContext(ctx context.Context, input string) (string, error) {
+	done := ctx.Done() // nil for context.Background(), which is never checked
+	state := generatedModThreeLSBInitial
+	steps := 0
+	for pos, char := range input {
+		if done != nil && steps&(generatedModThreeLSBCheckInterval-1) == 0 {
+			select {
+			case <-done:
+				return "", &fsm.AbortError{Kind: fsm.KindCanceled, State: generatedModThreeLSBStates[state], Steps: steps, Position: pos, Err: ctx.Err()}
+			default:
+			}
+		}
+		symbol := generatedModThreeLSBSymbol(char)
+		if symbol < 0 {
+			return "", &fsm.RunError{Kind: fsm.KindInvalidSymbol, State: generatedModThreeLSBStates[state], Symbol: string(char), Position: pos}
+		}
+		state = generatedModThreeLSBDelta[state][symbol]
+		steps++
+	}
+	return generatedModThreeLSBStates[state], nil
+}
+
+// Execute implements fsm.Automaton.
+func (fa generatedModThreeLSB) Execute(input string) (fsm.RunResult, error) {
+	return fa.ExecuteContext(context.Background(), input)
+}
+
+// ExecuteContext is Execute that stops like RunContext once ctx is done.
+func (generatedModThreeLSB) ExecuteContext(ctx context.Context, input string) (fsm.RunResult, error) {
+	done := ctx.Done()
+	var visits [len(generatedModThreeLSBStates)]int
+	state := generatedModThreeLSBInitial
+	visits[state]++
+	result := fsm.RunResult{FailedAt: -1}
+	var err error
+	for pos, char := range input {
+		if done != nil && result.Consumed&(generatedModThreeLSBCheckInterval-1) == 0 {
+			select {
+			case <-done:
+				err = &fsm.AbortError{Kind: fsm.KindCanceled, State: generatedModThreeLSBStates[state], Steps: result.Consumed, Position: pos, Err: ctx.Err()}
+			default:
+			}
+			if err != nil {
+				result.FailedAt = pos
+				break
+			}
+		}
+		symbol := generatedModThreeLSBSymbol(char)
+		if symbol < 0 {
+			err = &fsm.RunError{Kind: fsm.KindInvalidSymbol, State: generatedModThreeLSBStates[state], Symbol: string(char), Position: pos}
+			result.FailedAt = pos
+			break
+		}
+		state = generatedModThreeLSBDelta[state][symbol]
+		visits[state]++
+		result.Consumed++
+	}
+
+	result.FinalState = generatedModThreeLSBStates[state]
+	result.Visits = make(map[string]int)
+	for i, n := range visits {
+		if n > 0 {
+			result.Visits[generatedModThreeLSBStates[i]] = n
+		}
+	}
+	if err != nil {
+		return result, err
+	}
+	result.Accepted = generatedModThreeLSBAccepting[state]
+	return result, nil
+}
+
+// IsAccepting implements fsm.Automaton.
+func (generatedModThreeLSB) IsAccepting(state string) bool {
+	for i, name := range generatedModThreeLSBStates {
+		if name == state {
+			return generatedModThreeLSBAccepting[i]
+		}
+	}
+	return false
+}
+
+// ValidateInput implements fsm.Automaton.
+func (generatedModThreeLSB) ValidateInput(input string) bool {
+	for _, char := range input {
+		if generatedModThreeLSBSymbol(char) < 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+// Code generated by fsmgen from modthree_lsb.json. DO NOT EDIT.
+
+package mod3
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"modulo_three_advanced/fsm"
+)
+
+// TestGeneratedModThreeLSBAgreesWithInterpreter runs every input of up to
+// 9 symbols, over the alphabet plus a foreign symbol, through both
+// the generated and the interpreted automaton.
+func TestGeneratedModThreeLSBAgreesWithInterpreter(t *testing.T) {
+	interpreted, err := fsm.NewFiniteAutomaton(
+		[]string{"R0P1", "R0P2", "R1P1", "R1P2", "R2P1", "R2P2"},
+		[]string{"0", "1"},
+		"R0P1",
+		[]string{"R0P1", "R0P2", "R1P1", "R1P2", "R2P1", "R2P2"},
+		map[string]map[string]string{
+			"R0P1": {"0": "R0P2", "1": "R1P2"},
+			"R0P2": {"0": "R0P1", "1": "R2P1"},
+			"R1P1": {"0": "R1P2", "1": "R2P2"},
+			"R1P2": {"0": "R1P1", "1": "R0P1"},
+			"R2P1": {"0": "R2P2", "1": "R0P2"},
+			"R2P2": {"0": "R2P1", "1": "R1P1"},
+		},
+	)
+	if err != nil {
+		t.Fatalf("Failed to build the interpreted automaton: %v", err)
+	}
+	generated := generatedModThreeLSB{}
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	symbols := []string{"0", "1", "#"}
+	inputs := []string{""}
+	for frontier, length := []string{""}, 1; length <= 9; length++ {
+		var next []string
+		for _, prefix := range frontier {
+			for _, symbol := range symbols {
+				next = append(next, prefix+symbol)
+			}
+		}
+		inputs = append(inputs, next...)
+		frontier = next
+	}
+
+	for _, input := range inputs {
+		wantState, wantErr := interpreted.Run(input)
+		gotState, gotErr := generated.Run(input)
+		if gotState != wantState {
+			t.Fatalf("Run(%q): generated %q, interpreted %q", input, gotState, wantState)
+		}
+		var wantRunErr, gotRunErr *fsm.RunError
+		if errors.As(wantErr, &wantRunErr) != errors.As(gotErr, &gotRunErr) || (wantRunErr != nil && *wantRunErr != *gotRunErr) {
+			t.Fatalf("Run(%q): generated error %v, interpreted error %v", input, gotErr, wantErr)
+		}
+		wantResult, _ := interpreted.Execute(input)
+		gotResult, _ := generated.Execute(input)
+		if !reflect.DeepEqual(gotResult, wantResult) {
+			t.Fatalf("Execute(%q): generated %+v, interpreted %+v", input, gotResult, wantResult)
+		}
+		wantResult, wantErr = interpreted.(*fsm.FiniteAutomaton).ExecuteContext(canceled, input)
+		gotResult, gotErr = generated.ExecuteContext(canceled, input)
+		if !reflect.DeepEqual(gotResult, wantResult) || !reflect.DeepEqual(gotErr, wantErr) {
+			t.Fatalf("ExecuteContext(%q) with a canceled context: generated (%+v, %v), interpreted (%+v, %v)", input, gotResult, gotErr, wantResult, wantErr)
+		}
+		if generated.ValidateInput(input) != interpreted.ValidateInput(input) {
+			t.Fatalf("ValidateInput(%q) differs", input)
+		}
+	}
+
+	for _, state := range []string{"R0P1", "R0P2", "R1P1", "R1P2", "R2P1", "R2P2", ""} {
+		if generated.IsAccepting(state) != interpreted.IsAccepting(state) {
+			t.Errorf("IsAccepting(%q) differs", state)
+		}
+	}
+}
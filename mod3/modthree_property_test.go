@@ -0,0 +1,113 @@
+package mod3
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"modulo_three_advanced/fsm"
+	"modulo_three_advanced/fsm/fsmtest"
+)
+
+// Property-based tests: random inputs generated from the Mod-Three alphabet
+// are checked against math/big, which serves as the reference oracle.
+
+// bigMod returns the non-negative remainder of n modulo m; it is the oracle
+// every remainder test in this package checks against.
+func bigMod(n *big.Int, m int) int {
+	return int(new(big.Int).Mod(n, big.NewInt(int64(m))).Int64()) // Mod is Euclidean, never negative
+}
+
+// parseBinary reads a binary string with math/big. Like Calculate, it treats
+// empty and whitespace-only input as zero.
+func parseBinary(input string) (*big.Int, error) {
+	if strings.TrimSpace(input) == "" {
+		return new(big.Int), nil
+	}
+	// SetString accepts a leading sign, which is not a binary digit.
+	if strings.Trim(input, "01") != "" {
+		return nil, fmt.Errorf("not a binary number: %q", input)
+	}
+	n, ok := new(big.Int).SetString(input, 2)
+	if !ok {
+		return nil, fmt.Errorf("not a binary number: %q", input)
+	}
+	return n, nil
+}
+
+// setupPropertyTest builds the Mod-Three automaton, its calculator and a seeded generator.
+func setupPropertyTest(t *testing.T, seed uint64) (*fsm.FiniteAutomaton, ModuloCalculator, *fsmtest.Generator) {
+	t.Helper()
+	fa, err := buildAutomaton(GetModThreeConfig())
+	if err != nil {
+		t.Fatalf("Failed to build Mod-Three automaton: %v", err)
+	}
+	calc, err := NewModThreeCalculator(GetModThreeConfig())
+	if err != nil {
+		t.Fatalf("Failed to initialize calculator: %v", err)
+	}
+	g := fsmtest.NewGenerator(fa, rand.New(rand.NewPCG(seed, 3)))
+	g.MaxLength = 256
+	return fa, calc, g
+}
+
+func TestProperty_CalculateMatchesBigInt(t *testing.T) {
+	_, calc, g := setupPropertyTest(t, 1)
+	g.InvalidRate = 0.2
+
+	fsmtest.ForAll(t, g, func(input string) error {
+		actual, err := calc.Calculate(input)
+		n, oracleErr := parseBinary(input)
+		if (err != nil) != (oracleErr != nil) {
+			return fmt.Errorf("Calculate error = %v, math/big error = %v", err, oracleErr)
+		}
+		if err != nil {
+			return nil
+		}
+		if expected := bigMod(n, 3); actual != expected {
+			return fmt.Errorf("Calculate = %d, math/big = %d", actual, expected)
+		}
+		return nil
+	})
+}
+
+func TestProperty_AutomatonInvariants(t *testing.T) {
+	fa, _, g := setupPropertyTest(t, 2)
+	g.InvalidRate = 0.5
+
+	stateFor := []string{StateS0, StateS1, StateS2}
+	fsmtest.ForAll(t, g, fsmtest.AgreesWithOracle(fa, func(input string) (string, error) {
+		n, err := parseBinary(input)
+		if err != nil {
+			return "", err
+		}
+		return stateFor[bigMod(n, 3)], nil
+	}))
+	fsmtest.ForAll(t, g, fsmtest.InvalidImpliesRunError(fa))
+}
+
+// TestProperty_ConcatenationLaw checks rem(xy) = (rem(x)·2^|y| + rem(y)) mod 3,
+// where 2^|y| mod 3 is 1 for even |y| and 2 for odd |y|.
+func TestProperty_ConcatenationLaw(t *testing.T) {
+	_, calc, g := setupPropertyTest(t, 3)
+
+	fsmtest.ForAllPairs(t, g, func(x, y string) error {
+		rx, errX := calc.Calculate(x)
+		ry, errY := calc.Calculate(y)
+		rxy, errXY := calc.Calculate(x + y)
+		if errX != nil || errY != nil || errXY != nil {
+			return fmt.Errorf("unexpected errors: %v, %v, %v", errX, errY, errXY)
+		}
+
+		shift := 1
+		if len(y)%2 == 1 {
+			shift = 2
+		}
+		if expected := (rx*shift + ry) % 3; rxy != expected {
+			return fmt.Errorf("rem(xy) = %d, want (%d·%d + %d) mod 3 = %d", rxy, rx, shift, ry, expected)
+		}
+		return nil
+	})
+}
@@ -1,10 +1,17 @@
 package mod3
 
 import (
+	"bytes"
+	"log/slog"
+	"maps"
+	"math/big"
+	"math/rand/v2"
 	"strings"
 	"testing"
 	"errors"
+	"fmt"
 	"modulo_three_advanced/fsm" 
+	"modulo_three_advanced/tracing"
 )
 
 // MockAutomaton is retained here for any future isolated component testing, 
@@ -26,62 +33,105 @@ func (m *MockAutomaton) ValidateInput(input string) bool {
 	return m.MockValidateInput(input)
 }
 
+// Execute composes the mock functions the way an engine would, so the
+// calculator sees the same outcome through either method.
+func (m *MockAutomaton) Execute(input string) (fsm.RunResult, error) {
+	if !m.MockValidateInput(input) {
+		return fsm.RunResult{FailedAt: 0}, &fsm.RunError{Kind: fsm.KindInvalidSymbol, Position: 0}
+	}
+	state, err := m.MockRun(input)
+	if err != nil {
+		return fsm.RunResult{FailedAt: 0}, err
+	}
+	return fsm.RunResult{FinalState: state, Accepted: m.MockIsAccepting(state), Consumed: len(input), FailedAt: -1}, nil
+}
+
+// newAutomatonCalculator returns a ModThreeCalculator running fa, which
+// reads the states S0, S1 and S2 as remainders 0, 1 and 2.
+func newAutomatonCalculator(fa fsm.Automaton) *ModThreeCalculator {
+	e := &automatonEngine{fa: fa, remainders: map[string]int{StateS0: 0, StateS1: 1, StateS2: 2}, initial: StateS0}
+	return &ModThreeCalculator{newCalculator(e, calculatorOptions{})}
+}
+
 // -----------------------------------------------------------------------------
-// 1. UNIT TEST FOR StateToRemainder and IsStateAccepting
+// 1. UNIT TEST FOR stateRemainders
 // -----------------------------------------------------------------------------
 
-// TestStateToRemainder is retained as a unit test for the private helper method.
-func TestStateToRemainder(t *testing.T) {
-	// NOTE: We must instantiate a ModThreeCalculator to access its private methods in tests.
-	calc, _ := NewModThreeCalculator(GetModThreeConfig()) 
-	concreteCalc := calc.(*ModThreeCalculator) // Safely assume it's the concrete type for testing private helpers
+// TestStateRemainders checks which remainder each configured state is read
+// as, whatever the state names.
+func TestStateRemainders(t *testing.T) {
+	renamed := ModThreeFSMConfig{
+		States:          []string{"two", "zero", "one"},
+		Alphabet:        []string{Symbol1, Symbol0},
+		InitialState:    "zero",
+		AcceptingStates: []string{"zero", "one", "two"},
+		Transitions: map[string]map[string]string{
+			"zero": {Symbol0: "zero", Symbol1: "one"},
+			"one":  {Symbol0: "two", Symbol1: "zero"},
+			"two":  {Symbol0: "one", Symbol1: "two"},
+		},
+	}
+	// Tracks the value mod 6, so two states stand for each remainder.
+	mod6 := ModThreeFSMConfig{
+		States:          []string{"m0", "m1", "m2", "m3", "m4", "m5"},
+		Alphabet:        []string{Symbol0, Symbol1},
+		InitialState:    "m0",
+		AcceptingStates: []string{"m0", "m1", "m2", "m3", "m4", "m5"},
+		Transitions:     map[string]map[string]string{},
+	}
+	for v := range 6 {
+		mod6.Transitions[fmt.Sprintf("m%d", v)] = map[string]string{
+			Symbol0: fmt.Sprintf("m%d", 2*v%6), Symbol1: fmt.Sprintf("m%d", (2*v+1)%6),
+		}
+	}
+	// Tracks the parity of the number of ones, which says nothing about the remainder.
+	parity := ModThreeFSMConfig{
+		States:          []string{"even", "odd"},
+		Alphabet:        []string{Symbol0, Symbol1},
+		InitialState:    "even",
+		AcceptingStates: []string{"even", "odd"},
+		Transitions: map[string]map[string]string{
+			"even": {Symbol0: "even", Symbol1: "odd"},
+			"odd":  {Symbol0: "odd", Symbol1: "even"},
+		},
+	}
 
 	tests := []struct {
-		inputState string
-		expected   int
+		name     string
+		cfg      ModThreeFSMConfig
+		expected map[string]int
 	}{
-		{StateS0, 0},
-		{StateS1, 1},
-		{StateS2, 2},
-		{"InvalidState", -1}, // Ensures the default unknown state handler works
+		{"Default", GetModThreeConfig(), map[string]int{StateS0: 0, StateS1: 1, StateS2: 2}},
+		{"Renamed", renamed, map[string]int{"zero": 0, "one": 1, "two": 2}},
+		{"MergedStates", mod6, map[string]int{"m0": 0, "m1": 1, "m2": 2, "m3": 0, "m4": 1, "m5": 2}},
+		{"NotModThree", parity, map[string]int{}},
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.inputState, func(t *testing.T) {
-			actual := concreteCalc.stateToRemainder(tt.inputState) // Call the private method
-			if actual != tt.expected {
-				t.Errorf("stateToRemainder(%s): got %d, want %d", tt.inputState, actual, tt.expected)
+		t.Run(tt.name, func(t *testing.T) {
+			fa, err := buildAutomaton(tt.cfg)
+			if err != nil {
+				t.Fatalf("Failed to build automaton: %v", err)
+			}
+			actual := stateRemainders(fa.InitialState, transitionOnBit(fa.Freeze()), msbSpace)
+			if !maps.Equal(actual, tt.expected) {
+				t.Errorf("stateRemainders: got %v, want %v", actual, tt.expected)
 			}
 		})
 	}
-}
 
-func TestIsStateAccepting(t *testing.T) {
-    mockFA := &MockAutomaton{
-        MockIsAccepting: func(state string) bool {
-            return state == StateS0 // Only S0 is accepting for this mock
-        },
-    }
-    calc := &ModThreeCalculator{fa: mockFA}
-
-    tests := []struct {
-        state    string
-        expected bool
-    }{
-        {StateS0, true},
-        {StateS1, false},
-        {StateS2, false},
-        {"Unknown", false},
-    }
-
-    for _, tt := range tests {
-        t.Run(tt.state, func(t *testing.T) {
-            actual := calc.isStateAccepting(tt.state)
-            if actual != tt.expected {
-                t.Errorf("isStateAccepting(%s): got %t, want %t", tt.state, actual, tt.expected)
-            }
-        })
-    }
+	// A state reached by inputs with different remainders is reported as unknown.
+	calc, err := NewModThreeCalculator(parity)
+	if err != nil {
+		t.Fatalf("NewModThreeCalculator failed: %v", err)
+	}
+	if _, err := calc.Calculate("11"); !errors.Is(err, ErrUnknownState) {
+		t.Errorf("Expected ErrUnknownState, got %v", err)
+	}
+	calc, _ = NewModThreeCalculator(renamed)
+	if remainder, err := calc.Calculate("101010101"); err != nil || remainder != 2 {
+		t.Errorf("Calculate(101010101): got (%d, %v), want 2", remainder, err)
+	}
 }
 
 // -----------------------------------------------------------------------------
@@ -161,7 +211,7 @@ func TestCalculate_ErrorHandling(t *testing.T) {
             MockValidateInput: func(input string) bool { return true },
             MockIsAccepting: func(input string) bool { return true }, // Irrelevant for this path
         }
-        calc := &ModThreeCalculator{fa: mockFA}
+        calc := newAutomatonCalculator(mockFA)
         _, err := calc.Calculate("101")
         if err == nil || !strings.Contains(err.Error(), "mock FSM run error") {
             t.Errorf("Expected 'mock FSM run error', got %v", err)
@@ -180,7 +230,7 @@ func TestCalculate_ErrorHandling(t *testing.T) {
 		if faErr != nil { 
             t.Fatalf("Failed to create restrictive FiniteAutomaton for test: %v", faErr)
         }
-        calc := &ModThreeCalculator{fa: fa}
+        calc := newAutomatonCalculator(fa)
         _, err := calc.Calculate("0") // Goes to S1, which is not accepting
         if err == nil || !strings.Contains(err.Error(), "non-accepting state") {
             t.Errorf("Expected 'non-accepting state' error, got %v", err)
@@ -194,7 +244,7 @@ func TestCalculate_ErrorHandling(t *testing.T) {
             MockValidateInput: func(input string) bool { return true },
             MockIsAccepting: func(input string) bool { return true },
         }
-        calc := &ModThreeCalculator{fa: mockFA}
+        calc := newAutomatonCalculator(mockFA)
         _, err := calc.Calculate("101")
         if err == nil || !strings.Contains(err.Error(), "unknown state") {
             t.Errorf("Expected 'unknown state' error, got %v", err)
@@ -202,6 +252,44 @@ func TestCalculate_ErrorHandling(t *testing.T) {
     })
 }
 
+// TestCalculate_SentinelErrors checks that each failure of Calculate wraps the
+// sentinel error callers branch on with errors.Is.
+func TestCalculate_SentinelErrors(t *testing.T) {
+	valid, err := NewModThreeCalculator(GetModThreeConfig())
+	if err != nil {
+		t.Fatalf("Failed to initialize ModuloCalculator: %v", err)
+	}
+	restrictive, err := fsm.NewFiniteAutomaton([]string{StateS0, StateS1}, []string{Symbol0}, StateS0, []string{StateS0},
+		map[string]map[string]string{StateS0: {Symbol0: StateS1}, StateS1: {Symbol0: StateS0}})
+	if err != nil {
+		t.Fatalf("Failed to create restrictive FiniteAutomaton for test: %v", err)
+	}
+	unknown := &MockAutomaton{
+		MockRun:           func(input string) (string, error) { return "S99", nil },
+		MockValidateInput: func(input string) bool { return true },
+		MockIsAccepting:   func(input string) bool { return true },
+	}
+
+	tests := []struct {
+		name     string
+		calc     ModuloCalculator
+		input    string
+		expected error
+	}{
+		{"InvalidInput", valid, "1A0", ErrInvalidInput},
+		{"NonAcceptingFinalState", newAutomatonCalculator(restrictive), "0", ErrNonAccepting},
+		{"UnknownFinalState", newAutomatonCalculator(unknown), "101", ErrUnknownState},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.calc.Calculate(tt.input); !errors.Is(err, tt.expected) {
+				t.Errorf("Calculate(%q): expected error wrapping %v, got %v", tt.input, tt.expected, err)
+			}
+		})
+	}
+}
+
 // -----------------------------------------------------------------------------
 // INTEGRATION TEST FOR ModThree (Public API)
 // -----------------------------------------------------------------------------
@@ -295,4 +383,185 @@ func TestCalculator_Correctness(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+// -----------------------------------------------------------------------------
+// WITNESS TEST FOR the Mod-Three configuration
+// -----------------------------------------------------------------------------
+
+// TestModThreeConfig_ShortestWitnesses asserts the shortest input reaching each
+// remainder state, so a broken transition table is reported with a concrete example.
+func TestModThreeConfig_ShortestWitnesses(t *testing.T) {
+	cfg := GetModThreeConfig()
+	automaton, err := fsm.NewFiniteAutomaton(cfg.States, cfg.Alphabet, cfg.InitialState, cfg.AcceptingStates, cfg.Transitions)
+	if err != nil {
+		t.Fatalf("Failed to build Mod-Three automaton: %v", err)
+	}
+	fa := automaton.(*fsm.FiniteAutomaton)
+
+	expected := map[string]string{StateS0: "", StateS1: "1", StateS2: "10"}
+	witnesses := fsm.ShortestInputs(fa)
+	for state, want := range expected {
+		if got := witnesses[state]; got != want {
+			t.Errorf("Shortest input reaching %s: got %q, want %q", state, got, want)
+		}
+	}
+
+	// Every state is accepting, so there is no rejected witness.
+	if input, ok := fsm.ShortestRejected(fa); ok {
+		t.Errorf("Expected no rejected input for Mod-Three, got %q", input)
+	}
+
+	// The witnesses agree with the public calculator.
+	calc, _ := NewModThreeCalculator(cfg)
+	for _, input := range fsm.ShortestStrings(fa, StateS2, 5) {
+		if remainder, err := calc.Calculate(input); err != nil || remainder != 2 {
+			t.Errorf("Calculate(%q): got (%d, %v), want remainder 2", input, remainder, err)
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+// UNIT TEST FOR BucketSizes
+// -----------------------------------------------------------------------------
+
+func TestBucketSizes(t *testing.T) {
+	tests := []struct {
+		bitLength int
+		expected  []int64 // indexed by remainder
+	}{
+		{-1, []int64{0, 0, 0}}, // no inputs of negative length
+		{0, []int64{1, 0, 0}},  // only the empty input
+		{1, []int64{1, 1, 0}}, // 0, 1
+		{2, []int64{2, 1, 1}}, // 0, 1, 2, 3
+		{3, []int64{3, 3, 2}}, // 0..7
+		{8, []int64{86, 85, 85}},
+	}
+
+	for _, tt := range tests {
+		sizes, err := BucketSizes(tt.bitLength)
+		if err != nil {
+			t.Fatalf("BucketSizes(%d) failed: %v", tt.bitLength, err)
+		}
+		for remainder, want := range tt.expected {
+			if sizes[remainder].Int64() != want {
+				t.Errorf("BucketSizes(%d)[%d]: got %s, want %d", tt.bitLength, remainder, sizes[remainder], want)
+			}
+		}
+	}
+
+	// Large bit lengths stay exact: the buckets always sum to 2^n.
+	sizes, _ := BucketSizes(256)
+	total := new(big.Int)
+	for _, size := range sizes {
+		total.Add(total, size)
+	}
+	if want := new(big.Int).Lsh(big.NewInt(1), 256); total.Cmp(want) != 0 {
+		t.Errorf("BucketSizes(256) sum: got %s, want 2^256", total)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// SAMPLED INPUT TEST FOR Calculate
+// -----------------------------------------------------------------------------
+
+// TestCalculate_SampledRemainders draws random inputs guaranteed to end in each
+// remainder state and checks the calculator agrees.
+func TestCalculate_SampledRemainders(t *testing.T) {
+	fa, err := buildAutomaton(GetModThreeConfig())
+	if err != nil {
+		t.Fatalf("Failed to build Mod-Three automaton: %v", err)
+	}
+	calc, _ := NewModThreeCalculator(GetModThreeConfig())
+	sampler := fsm.NewSampler(fa, rand.New(rand.NewPCG(2024, 3)))
+
+	for remainder, state := range []string{StateS0, StateS1, StateS2} {
+		for i := 0; i < 50; i++ {
+			input, err := sampler.SampleEndingIn(128, state)
+			if err != nil {
+				t.Fatalf("SampleEndingIn(128, %s) failed: %v", state, err)
+			}
+			if actual, err := calc.Calculate(input); err != nil || actual != remainder {
+				t.Errorf("Calculate(%s): got (%d, %v), want %d", input, actual, err, remainder)
+			}
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+// UNIT TEST FOR Logging options
+// -----------------------------------------------------------------------------
+
+func TestNewModThreeCalculator_WithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	calc, err := NewModThreeCalculator(GetModThreeConfig(), WithLogger(logger), WithFSMOptions(fsm.WithTransitionLogging()))
+	if err != nil {
+		t.Fatalf("NewModThreeCalculator failed: %v", err)
+	}
+
+	if _, err := calc.Calculate("11"); err != nil {
+		t.Fatalf("Calculate(\"11\") failed: %v", err)
+	}
+	if strings.Count(buf.String(), "FSM transition") != 2 {
+		t.Errorf("Expected two transition records, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	calc.Calculate("1A0")
+	out := buf.String()
+	for _, want := range []string{"symbol=A position=1", "Mod-Three calculation failed", "input_length=3"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected log to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	// Configuration failures are logged by the engine.
+	buf.Reset()
+	cfg := GetModThreeConfig()
+	cfg.InitialState = "S9"
+	if _, err := NewModThreeCalculator(cfg, WithLogger(logger)); err == nil || !strings.Contains(buf.String(), "level=ERROR") {
+		t.Errorf("Expected logged config failure, got err=%v log:\n%s", err, buf.String())
+	}
+}
+
+// -----------------------------------------------------------------------------
+// UNIT TEST FOR Tracing option
+// -----------------------------------------------------------------------------
+
+func TestNewModThreeCalculator_WithTracer(t *testing.T) {
+	rec := tracing.NewRecorder()
+	calc, err := NewModThreeCalculator(GetModThreeConfig(), WithTracer(rec))
+	if err != nil {
+		t.Fatalf("NewModThreeCalculator failed: %v", err)
+	}
+
+	calc.Calculate("1101")
+	calc.Calculate("1A01")
+
+	spans := rec.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 Calculate spans (engine not traced by default), got %d", len(spans))
+	}
+	ok, bad := spans[0], spans[1]
+	if ok.Name != "mod3.Calculate" || ok.Attributes[tracing.KeyRemainder] != 1 ||
+		ok.Attributes[tracing.KeyFinalState] != StateS1 || ok.Attributes[tracing.KeyInputLength] != 4 {
+		t.Errorf("Unexpected success span: %+v", ok)
+	}
+	if !errors.Is(bad.Err, ErrInvalidInput) || bad.Attributes[tracing.KeyRemainder] != nil {
+		t.Errorf("Unexpected error span: %+v", bad)
+	}
+
+	// Forwarding the tracer to the engine adds construction and Run spans.
+	rec.Reset()
+	calc, _ = NewModThreeCalculator(GetModThreeConfig(), WithTracer(rec), WithFSMOptions(fsm.WithTracer(rec)))
+	calc.Calculate("10")
+	names := []string{}
+	for _, s := range rec.Spans() {
+		names = append(names, s.Name)
+	}
+	if strings.Join(names, ",") != "fsm.NewFiniteAutomaton,fsm.Run,mod3.Calculate" {
+		t.Errorf("Unexpected span sequence: %v", names)
+	}
+}
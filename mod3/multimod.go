@@ -0,0 +1,255 @@
+package mod3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"modulo_three_advanced/fsm"
+	"modulo_three_advanced/tracing"
+)
+
+// ErrUnsupportedModulus is returned (wrapped) when a MultiModCalculator is
+// asked about a modulus that divides none of its configured moduli.
+var ErrUnsupportedModulus = errors.New("FSM modulus not supported by the calculator")
+
+// GetModuloConfig returns the automaton computing remainders modulo modulus,
+// the generalisation of GetModThreeConfig. Under MSBFirst its states are
+// "R0" to "R<modulus-1>" and reading bit b in Rr moves to R((2r + b) mod
+// modulus). Under LSBFirst they are the reachable (remainder, 2^k mod
+// modulus) pairs, named like GetModThreeLSBConfig's. Every state is
+// accepting. The automaton has O(modulus) states, so keep moduli small.
+func GetModuloConfig(modulus int, order BitOrder) (ModThreeFSMConfig, error) {
+	if modulus < 1 {
+		return ModThreeFSMConfig{}, fmt.Errorf("invalid modulus %d: must be at least 1", modulus)
+	}
+	cfg, _ := moduloConfig(modulus, order)
+	return cfg, nil
+}
+
+// moduloConfig builds GetModuloConfig's automaton and maps each of its
+// states to the remainder it stands for.
+func moduloConfig(modulus int, order BitOrder) (ModThreeFSMConfig, map[string]int) {
+	type residue struct{ remainder, power int }
+	name := func(s residue) string {
+		if order == LSBFirst {
+			return fmt.Sprintf("R%dP%d", s.remainder, s.power)
+		}
+		return fmt.Sprintf("R%d", s.remainder)
+	}
+	next := func(s residue, bit int) residue {
+		if order == LSBFirst {
+			return residue{(s.remainder + bit*s.power) % modulus, 2 * s.power % modulus}
+		}
+		// Rnew = (2 × Rold + Bit) mod m
+		return residue{(2*s.remainder + bit) % modulus, 0}
+	}
+
+	// Breadth-first from the initial state, so only reachable states are declared.
+	initial := residue{0, 0}
+	if order == LSBFirst {
+		initial.power = 1 % modulus
+	}
+	remainders := map[string]int{name(initial): 0}
+	states := []string{name(initial)}
+	transitions := make(map[string]map[string]string)
+	for queue := []residue{initial}; len(queue) > 0; queue = queue[1:] {
+		current := queue[0]
+		row := make(map[string]string, 2)
+		for bit, symbol := range []string{Symbol0, Symbol1} {
+			to := next(current, bit)
+			if _, seen := remainders[name(to)]; !seen {
+				remainders[name(to)] = to.remainder
+				states = append(states, name(to))
+				queue = append(queue, to)
+			}
+			row[symbol] = name(to)
+		}
+		transitions[name(current)] = row
+	}
+
+	return ModThreeFSMConfig{
+		States:          states,
+		Alphabet:        []string{Symbol0, Symbol1},
+		InitialState:    name(initial),
+		AcceptingStates: states,
+		Transitions:     transitions,
+	}, remainders
+}
+
+// MultiModCalculator computes the remainders of an input modulo several
+// moduli in a single pass, by running the fsm.Product of one GetModuloConfig
+// automaton per modulus. Its final state holds every remainder at once.
+type MultiModCalculator struct {
+	moduli     []int
+	fa         *fsm.Sealed
+	remainders map[string][]int // per product state, in the order of moduli
+	logger     *slog.Logger     // Optional; nil keeps the calculator silent.
+	tracer     tracing.Tracer   // Optional span instrumentation.
+
+	format inputFormat // Encoding, bit order and normalization of the input.
+	limits limits      // WithMaxSteps and WithMaxInputLength.
+}
+
+// NewMultiModCalculator returns a calculator for the given moduli, e.g.
+// NewMultiModCalculator([]int{3, 4, 5, 9}). The product has at most
+// lcm(moduli) states under MSBFirst. WithLogger, WithTracer, WithEncoding,
+// WithBitOrder and WithNormalization apply as for NewModThreeCalculator, and
+// WithFSMOptions is passed on to the product automaton.
+func NewMultiModCalculator(moduli []int, opts ...Option) (*MultiModCalculator, error) {
+	o := applyOptions(opts)
+	if len(moduli) == 0 {
+		return nil, fmt.Errorf("invalid moduli: at least one is required")
+	}
+	if err := o.format.validate(); err != nil {
+		return nil, err
+	}
+
+	// 1. One automaton per modulus.
+	components := make([]*fsm.FiniteAutomaton, len(moduli))
+	remainders := make([]map[string]int, len(moduli))
+	for i, m := range moduli {
+		if m < 1 {
+			return nil, fmt.Errorf("invalid modulus %d: must be at least 1", m)
+		}
+		var cfg ModThreeFSMConfig
+		cfg, remainders[i] = moduloConfig(m, o.format.order)
+		fa, err := buildAutomaton(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize FSM engine: %w", err)
+		}
+		components[i] = fa
+	}
+
+	// 2. Their product, run once per input.
+	product, err := fsm.Product(components, o.fsmOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize FSM engine: %w", err)
+	}
+
+	// 3. Precompute the remainders held by each product state.
+	calc := &MultiModCalculator{
+		moduli:     slices.Clone(moduli),
+		fa:         product.Freeze(),
+		remainders: make(map[string][]int, len(product.States)),
+		logger:     o.logger,
+		tracer:     o.tracer,
+		format:     o.format,
+		limits:     o.limits,
+	}
+	for state := range product.States {
+		rs := make([]int, len(moduli))
+		for i, component := range product.Components(state) {
+			rs[i] = remainders[i][component]
+		}
+		calc.remainders[state] = rs
+	}
+	return calc, nil
+}
+
+// Moduli returns the configured moduli, in the order Remainders reports them.
+func (c *MultiModCalculator) Moduli() []int {
+	return slices.Clone(c.moduli)
+}
+
+// Automaton returns the product automaton; use Thaw for fsm.Analyze.
+func (c *MultiModCalculator) Automaton() *fsm.Sealed {
+	return c.fa
+}
+
+// Remainders returns the non-negative remainder of input modulo each
+// configured modulus, in the order of Moduli. Input is read as by
+// ModThreeCalculator.Calculate and fails with the same errors.
+func (c *MultiModCalculator) Remainders(input string) ([]int, error) {
+	return c.RemaindersContext(context.Background(), input)
+}
+
+// RemaindersContext is Remainders that stops with an *fsm.AbortError once
+// ctx is done.
+func (c *MultiModCalculator) RemaindersContext(ctx context.Context, input string) ([]int, error) {
+	return observe(ctx, c.logger, c.tracer, len(input), func(ctx context.Context) ([]int, string, error) {
+		if err := c.limits.checkInput(len(input)); err != nil {
+			return nil, "", err
+		}
+		calculate := func(bits string) ([]int, string, error) { return c.calculate(ctx, bits) }
+		return wrapFormat(c.format, calculate, nil, c.negative)(input)
+	})
+}
+
+// Remainder returns the remainder of input modulo m, which must divide one
+// of the configured moduli; with moduli {4, 9}, m may be 2, 3, 4 or 9.
+func (c *MultiModCalculator) Remainder(input string, m int) (int, error) {
+	i := slices.IndexFunc(c.moduli, func(modulus int) bool { return m >= 1 && modulus%m == 0 })
+	if i < 0 {
+		return -1, fmt.Errorf("%w: %d", ErrUnsupportedModulus, m)
+	}
+	remainders, err := c.Remainders(input)
+	if err != nil {
+		return -1, err
+	}
+	return remainders[i] % m, nil
+}
+
+// IsDivisibleBy reports whether input is a multiple of m, which must divide
+// one of the configured moduli.
+func (c *MultiModCalculator) IsDivisibleBy(input string, m int) (bool, error) {
+	remainder, err := c.Remainder(input, m)
+	return err == nil && remainder == 0, err
+}
+
+// IsDivisibleByAll reports whether input is a multiple of every configured modulus.
+func (c *MultiModCalculator) IsDivisibleByAll(input string) (bool, error) {
+	remainders, err := c.Remainders(input)
+	if err != nil {
+		return false, err
+	}
+	return !slices.ContainsFunc(remainders, func(r int) bool { return r != 0 }), nil
+}
+
+// IsDivisibleBy reports whether the binary input is a multiple of m. It
+// builds a single-modulus automaton per call; keep a MultiModCalculator for
+// repeated checks or other options.
+func IsDivisibleBy(input string, m int) (bool, error) {
+	calc, err := NewMultiModCalculator([]int{m})
+	if err != nil {
+		return false, err
+	}
+	return calc.IsDivisibleBy(input, m)
+}
+
+func (c *MultiModCalculator) calculate(ctx context.Context, input string) ([]int, string, error) {
+	// Handle empty string case (value 0, remainder 0)
+	if strings.TrimSpace(input) == "" {
+		return make([]int, len(c.moduli)), "", nil
+	}
+
+	// Bits past the step limit are never read.
+	n, clipped := c.limits.clipText(input)
+	result, err := c.fa.ExecuteContext(ctx, input[:n])
+	if err != nil {
+		return nil, "", invalidInput(err, input)
+	}
+	finalState := result.FinalState
+	if clipped {
+		return nil, finalState, c.limits.stepLimit(finalState, n)
+	}
+	if !result.Accepted {
+		return nil, finalState, fmt.Errorf("%w: %s", ErrNonAccepting, finalState)
+	}
+	remainders, ok := c.remainders[finalState]
+	if !ok {
+		return nil, finalState, fmt.Errorf("%w: %s", ErrUnknownState, finalState)
+	}
+	return slices.Clone(remainders), finalState, nil
+}
+
+// negative corrects each remainder of the bits of a negative input.
+func (c *MultiModCalculator) negative(remainders []int) []int {
+	for i, m := range c.moduli {
+		remainders[i] = c.format.encoding.negative(remainders[i], m)
+	}
+	return remainders
+}
@@ -0,0 +1,177 @@
+package mod3
+
+import (
+	"errors"
+	"math/big"
+	"math/rand/v2"
+	"reflect"
+	"testing"
+
+	"modulo_three_advanced/fsm"
+)
+
+// -----------------------------------------------------------------------------
+// UNIT TESTS FOR MultiModCalculator
+// -----------------------------------------------------------------------------
+
+func TestGetModuloConfig(t *testing.T) {
+	for _, order := range []BitOrder{MSBFirst, LSBFirst} {
+		for _, m := range []int{1, 2, 3, 4, 5, 9, 10} {
+			cfg, err := GetModuloConfig(m, order)
+			if err != nil {
+				t.Fatalf("GetModuloConfig(%d, %s) failed: %v", m, order, err)
+			}
+			fa, err := buildAutomaton(cfg)
+			if err != nil {
+				t.Fatalf("%s mod %d: invalid config: %v", order, m, err)
+			}
+			if report := fsm.Analyze(fa); len(report.Unreachable) != 0 {
+				t.Errorf("%s mod %d: unreachable states %v", order, m, report.Unreachable)
+			}
+		}
+	}
+
+	// Mod 3 LSB-first reproduces GetModThreeLSBConfig.
+	cfg, _ := GetModuloConfig(3, LSBFirst)
+	if lsb := GetModThreeLSBConfig(); !reflect.DeepEqual(cfg.Transitions, lsb.Transitions) {
+		t.Errorf("GetModuloConfig(3, LSBFirst): got %v, want %v", cfg.Transitions, lsb.Transitions)
+	}
+
+	if _, err := GetModuloConfig(0, MSBFirst); err == nil {
+		t.Error("Expected an error for modulus 0")
+	}
+}
+
+func TestMultiModCalculator(t *testing.T) {
+	calc, err := NewMultiModCalculator([]int{3, 4, 5, 9})
+	if err != nil {
+		t.Fatalf("NewMultiModCalculator failed: %v", err)
+	}
+	if n := len(calc.Automaton().States()); n != 180 { // lcm(3, 4, 5, 9)
+		t.Errorf("Expected 180 product states, got %d", n)
+	}
+
+	tests := []struct {
+		input    string
+		expected []int
+		wantErr  error
+	}{
+		{"", []int{0, 0, 0, 0}, nil},
+		{"1101", []int{1, 1, 3, 4}, nil},     // 13
+		{"10110100", []int{0, 0, 0, 0}, nil}, // 180
+		{"11111111", []int{0, 3, 0, 3}, nil}, // 255
+		{"12", nil, ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		actual, err := calc.Remainders(tt.input)
+		if !reflect.DeepEqual(actual, tt.expected) || !errors.Is(err, tt.wantErr) {
+			t.Errorf("Remainders(%q): got (%v, %v), want (%v, %v)", tt.input, actual, err, tt.expected, tt.wantErr)
+		}
+	}
+
+	divisible := []struct {
+		input    string
+		m        int
+		expected bool
+		wantErr  error
+	}{
+		{"10110100", 9, true, nil},
+		{"10110100", 6, false, ErrUnsupportedModulus},
+		{"10110", 2, true, nil}, // 22: 2 divides 4
+		{"1101", 3, false, nil},
+		{"1101", 0, false, ErrUnsupportedModulus},
+		{"12", 3, false, ErrInvalidInput},
+	}
+	for _, tt := range divisible {
+		actual, err := calc.IsDivisibleBy(tt.input, tt.m)
+		if actual != tt.expected || !errors.Is(err, tt.wantErr) {
+			t.Errorf("IsDivisibleBy(%q, %d): got (%v, %v), want (%v, %v)", tt.input, tt.m, actual, err, tt.expected, tt.wantErr)
+		}
+	}
+
+	if all, err := calc.IsDivisibleByAll("10110100"); err != nil || !all {
+		t.Errorf("IsDivisibleByAll(180): got (%v, %v), want true", all, err)
+	}
+	if all, err := calc.IsDivisibleByAll("1111"); err != nil || all {
+		t.Errorf("IsDivisibleByAll(15): got (%v, %v), want false", all, err)
+	}
+	if ok, err := IsDivisibleBy("1111", 5); err != nil || !ok {
+		t.Errorf("IsDivisibleBy(15, 5): got (%v, %v), want true", ok, err)
+	}
+	if _, err := IsDivisibleBy("1111", -5); err == nil {
+		t.Error("IsDivisibleBy with modulus -5 should fail")
+	}
+
+	// Moduli returns a copy.
+	calc.Moduli()[0] = 7
+	if calc.Moduli()[0] != 3 {
+		t.Error("Moduli should not expose the calculator's slice")
+	}
+}
+
+func TestMultiModCalculator_Invalid(t *testing.T) {
+	for _, moduli := range [][]int{nil, {3, 0}, {-1}} {
+		if _, err := NewMultiModCalculator(moduli); err == nil {
+			t.Errorf("NewMultiModCalculator(%v): expected an error", moduli)
+		}
+	}
+	if _, err := NewMultiModCalculator([]int{3}, WithEncoding(TwosComplement(0))); err == nil {
+		t.Error("Expected an error for an invalid encoding")
+	}
+}
+
+func TestProperty_MultiModMatchesBigInt(t *testing.T) {
+	rng := rand.New(rand.NewPCG(4, 6))
+	moduli := []int{3, 4, 5, 9, 7, 1}
+	remaindersOf := func(n *big.Int) []int {
+		remainders := make([]int, len(moduli))
+		for i, m := range moduli {
+			remainders[i] = bigMod(n, m)
+		}
+		return remainders
+	}
+
+	for _, order := range []BitOrder{MSBFirst, LSBFirst} {
+		signed, err := NewMultiModCalculator(moduli, WithBitOrder(order), WithEncoding(SignMagnitude))
+		if err != nil {
+			t.Fatalf("NewMultiModCalculator failed: %v", err)
+		}
+		words, err := NewMultiModCalculator(moduli, WithBitOrder(order), WithEncoding(TwosComplement(64)), WithNormalization(LenientInput))
+		if err != nil {
+			t.Fatalf("NewMultiModCalculator failed: %v", err)
+		}
+
+		for range 200 {
+			// Sign-magnitude, up to 256 bits.
+			magnitude := randomBits(rng, 1+rng.IntN(256))
+			n := bitsToBig(magnitude, order)
+			input := magnitude
+			if rng.IntN(2) == 0 {
+				input = "-" + magnitude
+				n.Neg(n)
+			}
+			if actual, err := signed.Remainders(input); err != nil || !reflect.DeepEqual(actual, remaindersOf(n)) {
+				t.Fatalf("%s Remainders(%q): got (%v, %v), want %v", order, input, actual, err, remaindersOf(n))
+			}
+
+			// Two's complement, 64 bits.
+			input = randomBits(rng, 64)
+			n = bitsToBig(input, order)
+			if n.Bit(63) == 1 {
+				n.Sub(n, new(big.Int).Lsh(big.NewInt(1), 64))
+			}
+			if actual, err := words.Remainders(input); err != nil || !reflect.DeepEqual(actual, remaindersOf(n)) {
+				t.Fatalf("%s two's complement Remainders(%q): got (%v, %v), want %v", order, input, actual, err, remaindersOf(n))
+			}
+		}
+	}
+}
+
+// bitsToBig reads bits in the given order as an unsigned number.
+func bitsToBig(bits string, order BitOrder) *big.Int {
+	if order == LSBFirst {
+		bits = reverse(bits)
+	}
+	n, _ := new(big.Int).SetString(bits, 2)
+	return n
+}
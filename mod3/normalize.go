@@ -0,0 +1,191 @@
+package mod3
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Normalization selects the notational conveniences Calculate accepts. The
+// zero value accepts none of them, and whitespace-only input means 0.
+// Normalization applies to '0'/'1' text input, not to packed input.
+type Normalization struct {
+	// Prefixes accepts a leading "0b"/"0B", or "0x"/"0X" followed by
+	// hexadecimal digits. Under LSBFirst hexadecimal digits are read least
+	// significant first too, so the text is still the reverse of the MSB-first one.
+	Prefixes bool
+	// Separators ignores single '_' or ' ' separators between digits.
+	Separators bool
+	// TrailingNewline ignores one trailing "\n" or "\r\n".
+	TrailingNewline bool
+	// Strict accepts plain digits only; even whitespace-only input is
+	// rejected instead of meaning 0. It cannot be combined with the others.
+	Strict bool
+}
+
+var (
+	// StrictInput rejects everything but digits (and a sign, where the Encoding has one).
+	StrictInput = Normalization{Strict: true}
+	// LenientInput accepts every convenience, e.g. "0b1101_0011\n".
+	LenientInput = Normalization{Prefixes: true, Separators: true, TrailingNewline: true}
+)
+
+// InputError reports a character of the original input that Calculate
+// rejected under a Normalization. It matches ErrInvalidInput with errors.Is.
+type InputError struct {
+	Input    string
+	Position int    // byte offset in Input
+	Reason   string // e.g. "invalid character 'x'"
+}
+
+func (e *InputError) Error() string {
+	return fmt.Sprintf("%s: %s at position %d in %q", ErrInvalidInput, e.Reason, e.Position, e.Input)
+}
+
+// Unwrap makes InputError match ErrInvalidInput.
+func (e *InputError) Unwrap() error {
+	return ErrInvalidInput
+}
+
+func (n Normalization) validate() error {
+	if n.Strict && (n.Prefixes || n.Separators || n.TrailingNewline) {
+		return errors.New("invalid normalization: Strict cannot be combined with other options")
+	}
+	return nil
+}
+
+// apply reduces input to plain '0'/'1' digits, keeping a leading '-' when
+// signed is set. The zero Normalization returns input unchanged, leaving
+// validation to the automaton as before.
+func (n Normalization) apply(input string, signed bool, order BitOrder) (string, error) {
+	if n == (Normalization{}) {
+		return input, nil
+	}
+	var sb strings.Builder
+	sb.Grow(len(input))
+	err := n.scan(input, signed, order, func(b byte, _ int) bool {
+		sb.WriteByte(b)
+		return true
+	})
+	if err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// position maps offset k in the text apply returns for input back to the
+// byte offset in input of the character it came from, so errors about the
+// normalized text can point into the caller's input. An offset past the end
+// of the text maps to len(input).
+func (n Normalization) position(input string, k int, signed bool, order BitOrder) int {
+	if n == (Normalization{}) {
+		return k
+	}
+	position, emitted := len(input), 0
+	n.scan(input, signed, order, func(_ byte, at int) bool {
+		if emitted == k {
+			position = at
+			return false
+		}
+		emitted++
+		return true
+	})
+	return position
+}
+
+// scan walks input for apply, passing emit each byte of the normalized text
+// along with the offset in input of the character it came from. It stops
+// early, without error, once emit returns false.
+func (n Normalization) scan(input string, signed bool, order BitOrder, emit func(b byte, at int) bool) error {
+	// 1. Drop the trailing newline, then treat blank input as 0 unless strict.
+	end := len(input)
+	if n.TrailingNewline && strings.HasSuffix(input, "\n") {
+		end--
+		if strings.HasSuffix(input[:end], "\r") {
+			end--
+		}
+	}
+	if input[:end] == "" || (!n.Strict && strings.TrimSpace(input[:end]) == "") {
+		return nil
+	}
+
+	pos := 0
+
+	// 2. Keep the sign, then strip the base prefix.
+	if signed && strings.HasPrefix(input, "-") {
+		if !emit('-', 0) {
+			return nil
+		}
+		pos++
+	}
+	hex := false
+	if n.Prefixes && end-pos >= 2 && input[pos] == '0' {
+		switch input[pos+1] {
+		case 'b', 'B':
+			pos += 2
+		case 'x', 'X':
+			hex = true
+			pos += 2
+		}
+	}
+
+	// 3. Copy the digits, expanding hexadecimal ones to four bits.
+	digits := 0
+	for i := pos; i < end; {
+		r, size := utf8.DecodeRuneInString(input[i:end])
+		switch {
+		case n.Separators && (r == '_' || r == ' '):
+			// A separator must sit between two digits.
+			if i == pos || i+size == end || !isDigit(input[i-1], hex) || !isDigit(input[i+size], hex) {
+				return &InputError{Input: input, Position: i, Reason: fmt.Sprintf("misplaced separator %q", r)}
+			}
+		case r < utf8.RuneSelf && isDigit(byte(r), hex):
+			digits++
+			if !hex {
+				if !emit(byte(r), i) {
+					return nil
+				}
+				break
+			}
+			value := hexValue(byte(r))
+			for k := range 4 {
+				shift := 3 - k
+				if order == LSBFirst {
+					shift = k
+				}
+				if !emit('0'+(value>>shift)&1, i) {
+					return nil
+				}
+			}
+		default:
+			return &InputError{Input: input, Position: i, Reason: fmt.Sprintf("invalid character %q", r)}
+		}
+		i += size
+	}
+	if digits == 0 {
+		return &InputError{Input: input, Position: end, Reason: "missing digits"}
+	}
+	return nil
+}
+
+func isDigit(b byte, hex bool) bool {
+	if b == '0' || b == '1' {
+		return true
+	}
+	return hex && hexValue(b) != 0xff
+}
+
+// hexValue returns the value of a hexadecimal digit, or 0xff.
+func hexValue(b byte) byte {
+	switch {
+	case '0' <= b && b <= '9':
+		return b - '0'
+	case 'a' <= b && b <= 'f':
+		return b - 'a' + 10
+	case 'A' <= b && b <= 'F':
+		return b - 'A' + 10
+	default:
+		return 0xff
+	}
+}
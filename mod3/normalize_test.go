@@ -0,0 +1,131 @@
+package mod3
+
+import (
+	"errors"
+	"math/big"
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+// -----------------------------------------------------------------------------
+// UNIT TESTS FOR WithNormalization
+// -----------------------------------------------------------------------------
+
+func TestNormalization(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		input    string
+		expected int
+		position int // of the *InputError; -1 when none is expected
+		wantErr  error
+	}{
+		// The zero Normalization keeps the original behaviour.
+		{"Default rejects prefix", nil, "0b1101", -1, -1, ErrInvalidInput},
+		{"Default blank", nil, "  ", 0, -1, nil},
+
+		{"Binary prefix", []Option{WithNormalization(LenientInput)}, "0b1101", 1, -1, nil},
+		{"Upper-case prefix", []Option{WithNormalization(LenientInput)}, "0B1110", 2, -1, nil},
+		{"Hex prefix", []Option{WithNormalization(LenientInput)}, "0xd", 1, -1, nil},
+		{"Hex digits", []Option{WithNormalization(LenientInput)}, "0xFF_ff", 0, -1, nil},    // 65535
+		{"Underscores", []Option{WithNormalization(LenientInput)}, "1101_0011", 1, -1, nil}, // 211
+		{"Spaces", []Option{WithNormalization(LenientInput)}, "1101 0011", 1, -1, nil},
+		{"Newline", []Option{WithNormalization(LenientInput)}, "1101\n", 1, -1, nil},
+		{"CRLF", []Option{WithNormalization(LenientInput)}, "0b1101\r\n", 1, -1, nil},
+		{"Blank", []Option{WithNormalization(LenientInput)}, " \n", 0, -1, nil},
+		{"Hex digit without prefix", []Option{WithNormalization(LenientInput)}, "1a", -1, 1, ErrInvalidInput},
+		{"Leading separator", []Option{WithNormalization(LenientInput)}, "_1101", -1, 0, ErrInvalidInput},
+		{"Trailing separator", []Option{WithNormalization(LenientInput)}, "1101_", -1, 4, ErrInvalidInput},
+		{"Double separator", []Option{WithNormalization(LenientInput)}, "11__01", -1, 2, ErrInvalidInput},
+		{"Separator after prefix", []Option{WithNormalization(LenientInput)}, "0b_1", -1, 2, ErrInvalidInput},
+		{"Prefix only", []Option{WithNormalization(LenientInput)}, "0x", -1, 2, ErrInvalidInput},
+		{"Two newlines", []Option{WithNormalization(LenientInput)}, "11\n\n", -1, 2, ErrInvalidInput},
+		{"Invalid character", []Option{WithNormalization(LenientInput)}, "0b10é1", -1, 4, ErrInvalidInput},
+		{"Separators only", []Option{WithNormalization(Normalization{Separators: true})}, "0b1", -1, 1, ErrInvalidInput},
+		{"Newline only", []Option{WithNormalization(Normalization{TrailingNewline: true})}, "11 0\n", -1, 2, ErrInvalidInput},
+
+		{"Strict digits", []Option{WithNormalization(StrictInput)}, "1110", 2, -1, nil},
+		{"Strict empty", []Option{WithNormalization(StrictInput)}, "", 0, -1, nil},
+		{"Strict blank", []Option{WithNormalization(StrictInput)}, " ", -1, 0, ErrInvalidInput},
+		{"Strict newline", []Option{WithNormalization(StrictInput)}, "11\n", -1, 2, ErrInvalidInput},
+
+		{"Signed prefix", []Option{WithNormalization(LenientInput), WithEncoding(SignMagnitude)}, "-0b1101", 2, -1, nil},
+		{"Signed hex", []Option{WithNormalization(LenientInput), WithEncoding(SignMagnitude)}, "-0xd\n", 2, -1, nil},
+		{"Sign only", []Option{WithNormalization(LenientInput), WithEncoding(SignMagnitude)}, "-", -1, 1, ErrInvalidInput},
+		{"Unsigned minus", []Option{WithNormalization(LenientInput)}, "-1", -1, 0, ErrInvalidInput},
+		{"Two's complement separators", []Option{WithNormalization(LenientInput), WithEncoding(TwosComplement(8))}, "1111_1101", 0, -1, nil}, // -3
+		{"Two's complement hex", []Option{WithNormalization(LenientInput), WithEncoding(TwosComplement(8))}, "0xff", 2, -1, nil},             // -1
+		{"Two's complement width", []Option{WithNormalization(LenientInput), WithEncoding(TwosComplement(8))}, "0xf", -1, -1, ErrInvalidWidth},
+
+		// LSB-first hex is the reverse of the MSB-first binary text: "0xb" LSB-first is 1101 = 0b1011 = 11.
+		{"LSB-first hex", []Option{WithNormalization(LenientInput), WithBitOrder(LSBFirst)}, "0xb", 2, -1, nil},
+		{"LSB-first hex digits", []Option{WithNormalization(LenientInput), WithBitOrder(LSBFirst)}, "0x01", 1, -1, nil}, // 16
+	}
+
+	for _, tt := range tests {
+		for name, calc := range calculatorFamily(t, tt.opts...) {
+			actual, err := calc.Calculate(tt.input)
+			if actual != tt.expected || !errors.Is(err, tt.wantErr) {
+				t.Errorf("%s %s Calculate(%q): got (%d, %v), want (%d, %v)", name, tt.name, tt.input, actual, err, tt.expected, tt.wantErr)
+				continue
+			}
+			var inputErr *InputError
+			if errors.As(err, &inputErr) != (tt.position >= 0) || (inputErr != nil && (inputErr.Position != tt.position || inputErr.Input != tt.input)) {
+				t.Errorf("%s %s Calculate(%q): got error %v, want position %d", name, tt.name, tt.input, err, tt.position)
+			}
+		}
+	}
+}
+
+func TestNormalization_Invalid(t *testing.T) {
+	bad := WithNormalization(Normalization{Strict: true, Separators: true})
+	if _, err := NewModThreeCalculator(GetModThreeConfig(), bad); err == nil {
+		t.Error("NewModThreeCalculator: expected an error for Strict with Separators")
+	}
+	if _, err := NewRemainderCalculator(bad); err == nil {
+		t.Error("NewRemainderCalculator: expected an error for Strict with Separators")
+	}
+	if _, err := NewGeneratedModThreeCalculator(bad); err == nil {
+		t.Error("NewGeneratedModThreeCalculator: expected an error for Strict with Separators")
+	}
+
+	err := &InputError{Input: "1x", Position: 1, Reason: "invalid character 'x'"}
+	if want := `FSM execution ended in validate Input: invalid character 'x' at position 1 in "1x"`; err.Error() != want {
+		t.Errorf("Error(): got %q, want %q", err.Error(), want)
+	}
+}
+
+func TestProperty_NormalizedMatchesBigInt(t *testing.T) {
+	rng := rand.New(rand.NewPCG(9, 3))
+	family := calculatorFamily(t, WithNormalization(LenientInput))
+
+	for range 300 {
+		bits := randomBits(rng, 1+rng.IntN(128))
+		n, _ := new(big.Int).SetString(bits, 2)
+
+		// Spell n with a random prefix and random separators.
+		digits := bits
+		prefix := []string{"", "0b", "0x"}[rng.IntN(3)]
+		if prefix == "0x" {
+			digits = n.Text(16)
+		}
+		var sb strings.Builder
+		sb.WriteString(prefix)
+		for i := range len(digits) {
+			if i > 0 && rng.IntN(4) == 0 {
+				sb.WriteByte("_ "[rng.IntN(2)])
+			}
+			sb.WriteByte(digits[i])
+		}
+		if rng.IntN(2) == 0 {
+			sb.WriteString("\n")
+		}
+
+		for name, calc := range family {
+			if got, err := calc.Calculate(sb.String()); err != nil || got != bigMod(n, 3) {
+				t.Fatalf("%s Calculate(%q) = (%d, %v), want %d", name, sb.String(), got, err, bigMod(n, 3))
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package mod3
+
+import (
+	"log/slog"
+
+	"modulo_three_advanced/fsm"
+	"modulo_three_advanced/tracing"
+)
+
+// Option configures optional behaviour of NewModThreeCalculator.
+type Option func(*calculatorOptions)
+
+// calculatorOptions collects the settings applied by Option values.
+type calculatorOptions struct {
+	logger     *slog.Logger
+	tracer     tracing.Tracer
+	fsmOptions []fsm.Option
+	format     inputFormat
+	limits     limits
+}
+
+func applyOptions(opts []Option) calculatorOptions {
+	var o calculatorOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithLogger logs failed calculations (warn level) to logger and passes it
+// on to the FSM engine, which logs config validation failures and rejected
+// symbols with their position.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *calculatorOptions) {
+		o.logger = logger
+		o.fsmOptions = append(o.fsmOptions, fsm.WithLogger(logger))
+	}
+}
+
+// WithFSMOptions forwards options to the underlying fsm.NewFiniteAutomaton,
+// e.g. WithFSMOptions(fsm.WithTransitionLogging()).
+func WithFSMOptions(opts ...fsm.Option) Option {
+	return func(o *calculatorOptions) { o.fsmOptions = append(o.fsmOptions, opts...) }
+}
+
+// WithTracer records a "mod3.Calculate" span per call with the input length,
+// final state, remainder and error. Engine spans are not forwarded by default;
+// add WithFSMOptions(fsm.WithTracer(t)) to also trace construction and Run.
+func WithTracer(tracer tracing.Tracer) Option {
+	return func(o *calculatorOptions) { o.tracer = tracer }
+}
+
+// WithEncoding sets how inputs are signed: Unsigned (the default),
+// SignMagnitude or TwosComplement(width).
+func WithEncoding(e Encoding) Option {
+	return func(o *calculatorOptions) { o.format.encoding = e }
+}
+
+// WithBitOrder sets the order in which input bits are read: MSBFirst (the
+// default) or LSBFirst, which runs an automaton tracking both the remainder
+// and the place value of the next bit, so the input is never reversed.
+// NewModThreeCalculator then uses GetModThreeLSBConfig in place of an
+// MSB-first config such as GetModThreeConfig.
+func WithBitOrder(order BitOrder) Option {
+	return func(o *calculatorOptions) { o.format.order = order }
+}
+
+// WithNormalization sets which notations Calculate accepts besides plain
+// digits, e.g. WithNormalization(LenientInput) for "0b1101_0011\n", or
+// WithNormalization(StrictInput) to reject even whitespace-only input.
+// Rejected input is reported as *InputError with its original position.
+func WithNormalization(n Normalization) Option {
+	return func(o *calculatorOptions) { o.format.normalization = n }
+}
+
+// WithMaxInputLength rejects input longer than n bytes (packed input: n
+// bytes of data) before reading it, with an *fsm.AbortError of kind
+// fsm.KindInputTooLong. n <= 0 means no limit, the default.
+func WithMaxInputLength(n int) Option {
+	return func(o *calculatorOptions) { o.limits.maxInputLength = n }
+}
+
+// WithMaxSteps stops a calculation after n bits, one transition each, with
+// an *fsm.AbortError of kind fsm.KindStepLimit recording the state reached.
+// The limit counts bits after normalization; CalculateBig reads whole bytes of
+// the magnitude. The error's Steps is the number of bits read and its Position
+// the byte offset in the original input of the first unread one (its bit
+// index for packed input). n <= 0 means no limit, the default.
+func WithMaxSteps(n int) Option {
+	return func(o *calculatorOptions) { o.limits.maxSteps = n }
+}
@@ -0,0 +1,235 @@
+package mod3
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// PackedCalculator is implemented by the calculators in this package, which
+// also accept binary input packed 8 bits per byte instead of as '0'/'1' text.
+//
+// Packed bits are read in the calculator's bit order: under MSBFirst from
+// the top bit of each byte (or word) down, so data holds a big-endian
+// number; under LSBFirst from the bottom bit up, so data holds a
+// little-endian one. Only the first bitLength bits read are used.
+type PackedCalculator interface {
+	ModuloCalculator
+	// CalculateBytes returns the remainder of the first bitLength bits of data.
+	CalculateBytes(data []byte, bitLength int) (int, error)
+	// CalculateWords is CalculateBytes over 64-bit words.
+	CalculateWords(words []uint64, bitLength int) (int, error)
+	// CalculateBig returns the non-negative remainder of n, whatever its sign
+	// and whatever the configured Encoding.
+	CalculateBig(n *big.Int) (int, error)
+}
+
+var (
+	_ PackedCalculator = (*ModThreeCalculator)(nil)
+	_ PackedCalculator = (*RemainderCalculator)(nil)
+)
+
+// packed is bit-packed input of length bytes, read through byteAt so byte
+// slices, word slices and big.Int magnitudes share one code path without
+// being copied.
+type packed struct {
+	byteAt    func(i int) byte
+	length    int // in bytes
+	bitLength int
+}
+
+func packedBytes(data []byte, bitLength int) packed {
+	return packed{byteAt: func(i int) byte { return data[i] }, length: len(data), bitLength: bitLength}
+}
+
+// packedWords splits each word into bytes in reading order: high byte first
+// under MSBFirst, low byte first under LSBFirst.
+func packedWords(words []uint64, bitLength int, order BitOrder) packed {
+	byteAt := func(i int) byte { return byte(words[i/8] >> (56 - 8*(i%8))) }
+	if order == LSBFirst {
+		byteAt = func(i int) byte { return byte(words[i/8] >> (8 * (i % 8))) }
+	}
+	return packed{byteAt: byteAt, length: 8 * len(words), bitLength: bitLength}
+}
+
+// packedBig lays out the magnitude of n in reading order.
+func packedBig(n *big.Int, order BitOrder) packed {
+	magnitude := n.Bytes() // big-endian
+	byteAt := func(i int) byte { return magnitude[i] }
+	if order == LSBFirst {
+		byteAt = func(i int) byte { return magnitude[len(magnitude)-1-i] }
+	}
+	return packed{byteAt: byteAt, length: len(magnitude), bitLength: 8 * len(magnitude)}
+}
+
+// bit returns the i-th bit read.
+func (p packed) bit(i int, order BitOrder) int {
+	return bitAt(p.byteAt(i/8), i%8, order)
+}
+
+// bitAt returns the k-th bit read from b: counting from the top under
+// MSBFirst and from the bottom under LSBFirst.
+func bitAt(b byte, k int, order BitOrder) int {
+	if order == LSBFirst {
+		return int(b>>k) & 1
+	}
+	return int(b>>(7-k)) & 1
+}
+
+// spell writes the bits of p as '0'/'1' text in reading order.
+func (p packed) spell(order BitOrder) string {
+	var sb strings.Builder
+	sb.Grow(p.bitLength)
+	for i := range p.bitLength {
+		sb.WriteByte('0' + byte(p.bit(i, order)))
+	}
+	return sb.String()
+}
+
+// calculatePacked checks p against the format and limits, runs it unsigned
+// and applies the encoding's sign correction.
+func (f inputFormat) calculatePacked(p packed, l limits, run func(packed) (int, string, error)) (int, string, error) {
+	if err := l.checkInput(p.length); err != nil {
+		return -1, "", err
+	}
+	if p.bitLength < 0 || p.bitLength > 8*p.length {
+		return -1, "", fmt.Errorf("%w: bit length %d out of range for %d bytes", ErrInvalidInput, p.bitLength, p.length)
+	}
+
+	// A two's-complement sign bit is the most significant one: read first
+	// under MSBFirst and last under LSBFirst. Packed input has no '-' sign.
+	negative := false
+	if e := f.encoding; e.kind == twosComplementKind {
+		if p.bitLength != e.width {
+			return -1, "", fmt.Errorf("%w: got %d bits, want %d", ErrInvalidWidth, p.bitLength, e.width)
+		}
+		signBit := 0
+		if f.order == LSBFirst {
+			signBit = p.bitLength - 1
+		}
+		negative = p.bit(signBit, f.order) == 1
+	}
+
+	remainder, finalState, err := l.runPacked(p, run)
+	if err != nil || !negative {
+		return remainder, finalState, err
+	}
+	return f.encoding.negative(remainder, 3), finalState, nil
+}
+
+// calculateBig runs the magnitude of n and negates the remainder for negative n.
+func calculateBig(n *big.Int, order BitOrder, l limits, run func(packed) (int, string, error)) (int, string, error) {
+	if n == nil {
+		return -1, "", fmt.Errorf("%w: nil *big.Int", ErrInvalidInput)
+	}
+	p := packedBig(n, order)
+	if err := l.checkInput(p.length); err != nil {
+		return -1, "", err
+	}
+	remainder, finalState, err := l.runPacked(p, run)
+	if err != nil || n.Sign() >= 0 {
+		return remainder, finalState, err
+	}
+	return SignMagnitude.negative(remainder, 3), finalState, nil
+}
+
+// -----------------------------------------------------------------------------
+// byteTable: eight transitions per lookup
+// -----------------------------------------------------------------------------
+
+// byteTable maps every state and byte value to the state reached after
+// reading the byte's eight bits in order, so packed input takes one table
+// lookup per byte instead of eight transitions.
+type byteTable[S comparable] struct {
+	states    []S
+	index     map[S]int
+	next      [][256]int
+	accepting []bool
+	order     BitOrder
+	step      func(state S, bit int) (S, bool)
+}
+
+// newByteTable precomputes the table from the single-bit transition step
+// and the automaton's accepting states. It returns nil if step is undefined
+// anywhere or leaves states; engines then read packed bits one at a time.
+func newByteTable[S comparable](states []S, order BitOrder, step func(state S, bit int) (S, bool), accepting func(S) bool) *byteTable[S] {
+	t := &byteTable[S]{states: states, index: make(map[S]int, len(states)), next: make([][256]int, len(states)), accepting: make([]bool, len(states)), order: order, step: step}
+	for i, s := range states {
+		t.index[s] = i
+		t.accepting[i] = accepting(s)
+	}
+	for i, s := range states {
+		for b := range 256 {
+			current := s
+			for k := range 8 {
+				next, ok := step(current, bitAt(byte(b), k, order))
+				if !ok {
+					return nil
+				}
+				current = next
+			}
+			j, ok := t.index[current]
+			if !ok {
+				return nil
+			}
+			t.next[i][b] = j
+		}
+	}
+	return t
+}
+
+// run reads the bits of p starting in initial and returns the final state
+// and whether it is accepting.
+func (t *byteTable[S]) run(initial S, p packed) (S, bool) {
+	current := t.index[initial]
+	whole := p.bitLength / 8
+	for i := range whole {
+		current = t.next[current][p.byteAt(i)]
+	}
+
+	// A trailing partial byte is read bit by bit.
+	state := t.states[current]
+	for i := 8 * whole; i < p.bitLength; i++ {
+		state, _ = t.step(state, p.bit(i, t.order))
+	}
+	return state, t.accepting[t.index[state]]
+}
+
+// -----------------------------------------------------------------------------
+// calculator
+// -----------------------------------------------------------------------------
+
+// CalculateBytes implements PackedCalculator.
+func (c *calculator) CalculateBytes(data []byte, bitLength int) (int, error) {
+	return observe(context.Background(), c.logger, c.tracer, len(data), func(context.Context) (int, string, error) {
+		return c.format.calculatePacked(packedBytes(data, bitLength), c.limits, c.runPacked)
+	})
+}
+
+// CalculateWords implements PackedCalculator.
+func (c *calculator) CalculateWords(words []uint64, bitLength int) (int, error) {
+	return observe(context.Background(), c.logger, c.tracer, 8*len(words), func(context.Context) (int, string, error) {
+		return c.format.calculatePacked(packedWords(words, bitLength, c.format.order), c.limits, c.runPacked)
+	})
+}
+
+// CalculateBig implements PackedCalculator. A nil n is reported as
+// ErrInvalidInput.
+func (c *calculator) CalculateBig(n *big.Int) (int, error) {
+	size := 0
+	if n != nil {
+		size = (n.BitLen() + 7) / 8
+	}
+	return observe(context.Background(), c.logger, c.tracer, size, func(context.Context) (int, string, error) {
+		return calculateBig(n, c.format.order, c.limits, c.runPacked)
+	})
+}
+
+func (c *calculator) runPacked(p packed) (int, string, error) {
+	out, err := c.engine.runPacked(p)
+	if err != nil {
+		return -1, "", err
+	}
+	return c.finish(out)
+}
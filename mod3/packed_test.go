@@ -0,0 +1,183 @@
+package mod3
+
+import (
+	"errors"
+	"math/big"
+	"math/rand/v2"
+	"testing"
+
+	"modulo_three_advanced/fsm"
+)
+
+// -----------------------------------------------------------------------------
+// UNIT TESTS FOR PackedCalculator
+// -----------------------------------------------------------------------------
+
+// packedFamily returns every calculator implementation as a PackedCalculator.
+func packedFamily(t *testing.T, opts ...Option) map[string]PackedCalculator {
+	t.Helper()
+	family := make(map[string]PackedCalculator)
+	for name, calc := range calculatorFamily(t, opts...) {
+		family[name] = calc.(PackedCalculator)
+	}
+	return family
+}
+
+func TestCalculateBytes(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []Option
+		data      []byte
+		bitLength int
+		expected  int
+		wantErr   error
+	}{
+		{"Empty", nil, nil, 0, 0, nil},
+		{"One byte", nil, []byte{0x0d}, 8, 1, nil},                    // 13
+		{"Two bytes", nil, []byte{0x01, 0x00}, 16, 1, nil},            // 256
+		{"Partial byte", nil, []byte{0xd0}, 4, 1, nil},                // 0b1101 = 13
+		{"Trailing bits ignored", nil, []byte{0x0e, 0xff}, 8, 2, nil}, // 14
+		{"Too long", nil, []byte{0x01}, 9, -1, ErrInvalidInput},
+		{"Negative length", nil, []byte{0x01}, -1, -1, ErrInvalidInput},
+		{"LSB-first", []Option{WithBitOrder(LSBFirst)}, []byte{0x0d}, 8, 1, nil},
+		{"LSB-first little-endian", []Option{WithBitOrder(LSBFirst)}, []byte{0x00, 0x01}, 16, 1, nil}, // 256
+		{"LSB-first partial byte", []Option{WithBitOrder(LSBFirst)}, []byte{0xfd}, 4, 1, nil},         // low nibble 0b1101
+		{"Two's complement", []Option{WithEncoding(TwosComplement(8))}, []byte{0xff}, 8, 2, nil},      // -1
+		{"Two's complement positive", []Option{WithEncoding(TwosComplement(8))}, []byte{0x7f}, 8, 1, nil},
+		{"Two's complement partial", []Option{WithEncoding(TwosComplement(4))}, []byte{0xb0}, 4, 1, nil}, // 0b1011 = -5
+		{"Two's complement width", []Option{WithEncoding(TwosComplement(8))}, []byte{0xff, 0xff}, 16, -1, ErrInvalidWidth},
+		{"Two's complement LSB-first", []Option{WithEncoding(TwosComplement(8)), WithBitOrder(LSBFirst)}, []byte{0x80}, 8, 1, nil}, // -128
+		{"Sign-magnitude is unsigned", []Option{WithEncoding(SignMagnitude)}, []byte{0x0d}, 8, 1, nil},
+	}
+
+	for _, tt := range tests {
+		for name, calc := range packedFamily(t, tt.opts...) {
+			actual, err := calc.CalculateBytes(tt.data, tt.bitLength)
+			if actual != tt.expected || !errors.Is(err, tt.wantErr) {
+				t.Errorf("%s %s: got (%d, %v), want (%d, %v)", name, tt.name, actual, err, tt.expected, tt.wantErr)
+			}
+		}
+	}
+}
+
+func TestCalculateWords(t *testing.T) {
+	words := []uint64{0x8000000000000000, 0x0000000000000003} // 2^127 + 3
+	for name, calc := range packedFamily(t) {
+		if actual, err := calc.CalculateWords(words, 128); err != nil || actual != 2 {
+			t.Errorf("%s MSB-first: got (%d, %v), want 2", name, actual, err)
+		}
+		if actual, err := calc.CalculateWords(words, 8); err != nil || actual != 2 { // 0x80
+			t.Errorf("%s MSB-first prefix: got (%d, %v), want 2", name, actual, err)
+		}
+	}
+	for name, calc := range packedFamily(t, WithBitOrder(LSBFirst)) {
+		// Little-endian words: 0x8000000000000000 + 3·2^64
+		if actual, err := calc.CalculateWords(words, 128); err != nil || actual != 2 {
+			t.Errorf("%s LSB-first: got (%d, %v), want 2", name, actual, err)
+		}
+		if _, err := calc.CalculateWords(words, 129); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("%s: expected ErrInvalidInput, got %v", name, err)
+		}
+	}
+}
+
+func TestCalculateBig(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithBitOrder(LSBFirst)}, {WithEncoding(TwosComplement(4))}} {
+		for name, calc := range packedFamily(t, opts...) {
+			for n, want := range map[int64]int{0: 0, 13: 1, -13: 2, -1: 2, 3 << 40: 0} {
+				if actual, err := calc.CalculateBig(big.NewInt(n)); err != nil || actual != want {
+					t.Errorf("%s CalculateBig(%d): got (%d, %v), want %d", name, n, actual, err, want)
+				}
+			}
+			if actual, err := calc.CalculateBig(nil); actual != -1 || !errors.Is(err, ErrInvalidInput) {
+				t.Errorf("%s CalculateBig(nil): got (%d, %v), want ErrInvalidInput", name, actual, err)
+			}
+		}
+	}
+}
+
+// TestRunPacked_WithoutByteTable checks that engines whose transitions cannot
+// be tabled by byte still read packed input, one bit at a time.
+func TestRunPacked_WithoutByteTable(t *testing.T) {
+	fa, err := RemainderAutomaton()
+	if err != nil {
+		t.Fatalf("RemainderAutomaton failed: %v", err)
+	}
+	// State 2 is left out of the table's states.
+	typedEngine := newRemainderEngine(fa, []int{0, 1}, msbSpace)
+
+	partial := GetModThreeConfig()
+	delete(partial.Transitions[StateS2], Symbol1)
+	calc, err := NewModThreeCalculator(partial, WithFSMOptions(fsm.WithPartialTransitions()))
+	if err != nil {
+		t.Fatalf("NewModThreeCalculator failed: %v", err)
+	}
+	automaton := calc.(*ModThreeCalculator).engine.(*automatonEngine)
+
+	if typedEngine.table != nil || automaton.table != nil {
+		t.Fatal("Expected engines without byte tables")
+	}
+	for name, e := range map[string]engine{"typed": typedEngine, "automaton": automaton} {
+		out, err := e.runPacked(packedBytes([]byte{0x0d}, 8)) // 13
+		if err != nil || !out.accepted || out.remainder != 1 {
+			t.Errorf("%s runPacked(13): got (%+v, %v), want remainder 1", name, out, err)
+		}
+	}
+	// 0b1011: S0 -1-> S1 -0-> S2, which has no transition for the third bit.
+	var runErr *fsm.RunError
+	if _, err := calc.(PackedCalculator).CalculateBytes([]byte{0xb0}, 4); !errors.As(err, &runErr) || runErr.Kind != fsm.KindNoTransition || runErr.Position != 2 {
+		t.Errorf("Expected the missing transition at bit 2, got %v", err)
+	}
+}
+
+func TestCalculateBytes_NonAccepting(t *testing.T) {
+	cfg := GetModThreeConfig()
+	cfg.AcceptingStates = []string{StateS0}
+	calc, err := NewModThreeCalculator(cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := calc.(PackedCalculator).CalculateBytes([]byte{0x0d}, 8); !errors.Is(err, ErrNonAccepting) {
+		t.Errorf("Expected ErrNonAccepting, got %v", err)
+	}
+}
+
+func TestProperty_PackedMatchesBigInt(t *testing.T) {
+	rng := rand.New(rand.NewPCG(8, 3))
+	msb := packedFamily(t)
+	lsb := packedFamily(t, WithBitOrder(LSBFirst))
+
+	for range 200 {
+		data := make([]byte, rng.IntN(64))
+		for i := range data {
+			data[i] = byte(rng.Uint32())
+		}
+		bitLength := rng.IntN(8*len(data) + 1)
+
+		// Reference: the first bitLength bits, as text in reading order.
+		msbText := packedBytes(data, bitLength).spell(MSBFirst)
+		lsbText := packedBytes(data, bitLength).spell(LSBFirst)
+		for name := range msb {
+			want, _ := msb[name].Calculate(msbText)
+			if got, err := msb[name].CalculateBytes(data, bitLength); err != nil || got != want {
+				t.Fatalf("%s MSB-first CalculateBytes = (%d, %v), Calculate(%q) = %d", name, got, err, msbText, want)
+			}
+			want, _ = lsb[name].Calculate(lsbText)
+			if got, err := lsb[name].CalculateBytes(data, bitLength); err != nil || got != want {
+				t.Fatalf("%s LSB-first CalculateBytes = (%d, %v), Calculate(%q) = %d", name, got, err, lsbText, want)
+			}
+		}
+
+		n := new(big.Int).SetBytes(data)
+		if rng.IntN(2) == 0 {
+			n.Neg(n)
+		}
+		for name := range msb {
+			for _, calc := range []PackedCalculator{msb[name], lsb[name]} {
+				if got, err := calc.CalculateBig(n); err != nil || got != bigMod(n, 3) {
+					t.Fatalf("%s CalculateBig(%s) = (%d, %v), want %d", name, n, got, err, bigMod(n, 3))
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package mod3
+
+import (
+	"fmt"
+	"strconv"
+
+	"modulo_three_advanced/fsm"
+	"modulo_three_advanced/fsm/typed"
+)
+
+// RemainderAutomaton returns the Mod-Three automaton over typed states: each
+// state is the remainder itself (0, 1 or 2) and each symbol is a bit value,
+// so no mapping from state names back to remainders is needed.
+func RemainderAutomaton() (*typed.FiniteAutomaton[int, int], error) {
+	remainders := []int{0, 1, 2}
+	transitions := make(map[int]map[int]int, len(remainders))
+	for _, r := range remainders {
+		// Rnew = (2 × Rold + Bit) mod 3
+		transitions[r] = map[int]int{0: (2 * r) % 3, 1: (2*r + 1) % 3}
+	}
+	return typed.New(remainders, []int{0, 1}, 0, remainders, transitions)
+}
+
+// RemainderCalculator is a ModuloCalculator built on RemainderAutomaton, or
+// LSBRemainderAutomaton under LSBFirst. Its final state is the remainder (or
+// carries it), so unlike ModThreeCalculator it needs no ModThreeFSMConfig
+// and no state-to-remainder mapping. Its automata are built privately and
+// never modified, so Calculate is safe for concurrent use.
+type RemainderCalculator struct {
+	*calculator
+	adapter fsm.Automaton
+}
+
+// NewRemainderCalculator returns a calculator on the typed engine. WithLogger,
+// WithTracer and WithEncoding apply as for NewModThreeCalculator;
+// WithFSMOptions has no effect because the typed engine takes no fsm options.
+func NewRemainderCalculator(opts ...Option) (*RemainderCalculator, error) {
+	o := applyOptions(opts)
+	if err := o.format.validate(); err != nil {
+		return nil, err
+	}
+
+	if o.format.order == LSBFirst {
+		fa, err := LSBRemainderAutomaton()
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize FSM engine: %w", err)
+		}
+		e := newRemainderEngine(fa, lsbStates(), lsbSpace)
+		return &RemainderCalculator{calculator: newCalculator(e, o), adapter: typed.Adapt(fa, parseBit, LSBState.String)}, nil
+	}
+	fa, err := RemainderAutomaton()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize FSM engine: %w", err)
+	}
+	e := newRemainderEngine(fa, []int{0, 1, 2}, msbSpace)
+	return &RemainderCalculator{calculator: newCalculator(e, o), adapter: typed.Adapt(fa, parseBit, strconv.Itoa)}, nil
+}
+
+// Adapter exposes the typed engine through the string-based fsm.Automaton
+// interface, with states named "0", "1" and "2", or "R0P1" to "R2P2" under
+// LSBFirst. The adapter shares the calculator's automaton, which must not be
+// modified through it.
+func (c *RemainderCalculator) Adapter() fsm.Automaton {
+	return c.adapter
+}
+
+// parseBit converts Symbol0 and Symbol1 to bit values.
+func parseBit(symbol string) (int, bool) {
+	switch symbol {
+	case Symbol0:
+		return 0, true
+	case Symbol1:
+		return 1, true
+	default:
+		return 0, false
+	}
+}
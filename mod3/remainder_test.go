@@ -0,0 +1,142 @@
+package mod3
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"modulo_three_advanced/fsm"
+	"modulo_three_advanced/fsm/fsmtest"
+	"modulo_three_advanced/tracing"
+)
+
+// -----------------------------------------------------------------------------
+// UNIT TESTS FOR RemainderCalculator (typed engine)
+// -----------------------------------------------------------------------------
+
+func TestRemainderAutomaton(t *testing.T) {
+	fa, err := RemainderAutomaton()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// 13 = 0b1101, 13 mod 3 = 1
+	if remainder, err := fa.Run([]int{1, 1, 0, 1}); err != nil || remainder != 1 {
+		t.Errorf("Run(1101): got (%d, %v), want 1", remainder, err)
+	}
+}
+
+func TestRemainderCalculator(t *testing.T) {
+	calc, err := NewRemainderCalculator()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		input    string
+		expected int
+		wantErr  error
+	}{
+		{"", 0, nil},
+		{"  ", 0, nil},
+		{"1101", 1, nil},
+		{"1110", 2, nil},
+		{"1111", 0, nil},
+		{"10a1", -1, ErrInvalidInput},
+		{"1 1", -1, ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		actual, err := calc.Calculate(tt.input)
+		if actual != tt.expected || !errors.Is(err, tt.wantErr) {
+			t.Errorf("Calculate(%q): got (%d, %v), want (%d, %v)", tt.input, actual, err, tt.expected, tt.wantErr)
+		}
+	}
+}
+
+// TestRemainderCalculator_ParsesLazily checks that the bits are not copied
+// out of the input: a []int copy alone would cost 8 bytes per input bit.
+func TestRemainderCalculator_ParsesLazily(t *testing.T) {
+	calc, err := NewRemainderCalculator()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	input := strings.Repeat("1011", 1<<18) // 1 MiB of bits; 16 ≡ 1, so this is 2^18 × 11 ≡ 2 (mod 3)
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	remainder, err := calc.Calculate(input)
+	runtime.ReadMemStats(&after)
+
+	if remainder != 2 || err != nil {
+		t.Errorf("Calculate: got (%d, %v), want 2", remainder, err)
+	}
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > uint64(len(input)) {
+		t.Errorf("Calculate allocated %d bytes for %d input bits", allocated, len(input))
+	}
+}
+
+func TestRemainderCalculator_Tracer(t *testing.T) {
+	rec := tracing.NewRecorder()
+	calc, err := NewRemainderCalculator(WithTracer(rec))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	calc.Calculate("1110")
+
+	spans := rec.Spans()
+	if len(spans) != 1 || spans[0].Attributes[tracing.KeyFinalState] != "2" || spans[0].Attributes[tracing.KeyRemainder] != 2 {
+		t.Errorf("Unexpected spans: %+v", spans)
+	}
+}
+
+func TestRemainderCalculator_Adapter(t *testing.T) {
+	calc, err := NewRemainderCalculator()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var adapter fsm.Automaton = calc.Adapter()
+
+	if state, err := adapter.Run("1110"); err != nil || state != "2" {
+		t.Errorf("Run(1110): got (%q, %v), want 2", state, err)
+	}
+	if !adapter.IsAccepting("0") || adapter.IsAccepting(StateS0) {
+		t.Error("IsAccepting should use the formatted state names")
+	}
+	if !adapter.ValidateInput("0101") || adapter.ValidateInput("012") {
+		t.Error("ValidateInput mismatch")
+	}
+
+	_, err = adapter.Run("11x")
+	var runErr *fsm.RunError
+	if !errors.As(err, &runErr) || runErr.Kind != fsm.KindInvalidSymbol || runErr.State != "0" || runErr.Position != 2 {
+		t.Errorf("Expected invalid symbol at 2 in state 0, got %v", err)
+	}
+}
+
+func TestProperty_RemainderCalculatorMatchesModThree(t *testing.T) {
+	_, calc, g := setupPropertyTest(t, 4)
+	g.InvalidRate = 0.2
+	typedCalc, err := NewRemainderCalculator()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	fsmtest.ForAll(t, g, func(input string) error {
+		want, wantErr := calc.Calculate(input)
+		got, err := typedCalc.Calculate(input)
+		if got != want || (err != nil) != (wantErr != nil) {
+			return fmt.Errorf("typed = (%d, %v), string = (%d, %v)", got, err, want, wantErr)
+		}
+		return nil
+	})
+	fsmtest.ForAll(t, g, fsmtest.AgreesWithOracle(typedCalc.Adapter(), func(input string) (string, error) {
+		n, err := parseBinary(input)
+		if err != nil {
+			return "", err
+		}
+		return strconv.Itoa(bigMod(n, 3)), nil
+	}))
+}
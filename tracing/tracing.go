@@ -0,0 +1,159 @@
+// Package tracing defines the small span interface used to instrument the
+// fsm and mod3 packages, modelled on OpenTelemetry so an adapter to a real
+// tracing SDK is a thin wrapper. Recorder is an in-memory implementation for tests.
+package tracing
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Attribute keys set by the instrumented packages.
+const (
+	KeyInputLength = "input.length"
+	KeyFinalState  = "fsm.final_state"
+	KeyRemainder   = "mod3.remainder"
+	KeyError       = "error"
+)
+
+// Attribute is a key/value pair attached to a span or event.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// String returns a string-valued attribute.
+func String(key, value string) Attribute { return Attribute{Key: key, Value: value} }
+
+// Int returns an int-valued attribute.
+func Int(key string, value int) Attribute { return Attribute{Key: key, Value: value} }
+
+// Tracer starts spans. Implementations must be safe for concurrent use.
+type Tracer interface {
+	// Start begins a span named name as a child of any span in ctx and
+	// returns a context carrying the new span.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a single timed operation.
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	AddEvent(name string, attrs ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// -----------------------------------------------------------------------------
+// Recorder: the in-memory Tracer
+// -----------------------------------------------------------------------------
+
+// Event is a named annotation recorded on a span.
+type Event struct {
+	Name       string
+	Attributes map[string]any
+}
+
+// RecordedSpan is a finished span captured by a Recorder.
+type RecordedSpan struct {
+	ID         int
+	ParentID   int // 0 for root spans
+	Name       string
+	Attributes map[string]any
+	Events     []Event
+	Err        error
+	Start, End time.Time
+}
+
+// Recorder is a Tracer that keeps every finished span in memory.
+type Recorder struct {
+	mu     sync.Mutex
+	nextID int
+	spans  []RecordedSpan
+}
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+type spanKey struct{}
+
+// Start implements Tracer.
+func (r *Recorder) Start(ctx context.Context, name string) (context.Context, Span) {
+	r.mu.Lock()
+	r.nextID++
+	id := r.nextID
+	r.mu.Unlock()
+
+	parentID := 0
+	if parent, ok := ctx.Value(spanKey{}).(*recordingSpan); ok {
+		parentID = parent.data.ID
+	}
+	span := &recordingSpan{
+		recorder: r,
+		data: RecordedSpan{
+			ID:         id,
+			ParentID:   parentID,
+			Name:       name,
+			Attributes: make(map[string]any),
+			Start:      time.Now(),
+		},
+	}
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// Spans returns the finished spans in the order they ended.
+func (r *Recorder) Spans() []RecordedSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]RecordedSpan(nil), r.spans...)
+}
+
+// Reset discards every recorded span.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = nil
+}
+
+// recordingSpan is owned by a single goroutine until End hands it to the Recorder.
+type recordingSpan struct {
+	recorder *Recorder
+	data     RecordedSpan
+	ended    bool
+}
+
+func (s *recordingSpan) SetAttributes(attrs ...Attribute) {
+	for _, a := range attrs {
+		s.data.Attributes[a.Key] = a.Value
+	}
+}
+
+func (s *recordingSpan) AddEvent(name string, attrs ...Attribute) {
+	event := Event{Name: name, Attributes: make(map[string]any, len(attrs))}
+	for _, a := range attrs {
+		event.Attributes[a.Key] = a.Value
+	}
+	s.data.Events = append(s.data.Events, event)
+}
+
+func (s *recordingSpan) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	s.data.Err = err
+	s.data.Attributes[KeyError] = err.Error()
+}
+
+func (s *recordingSpan) End() {
+	if s.ended {
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	s.recorder.spans = append(s.recorder.spans, s.data)
+}
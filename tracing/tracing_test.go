@@ -0,0 +1,47 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	rec := NewRecorder()
+
+	ctx, parent := rec.Start(context.Background(), "parent")
+	_, child := rec.Start(ctx, "child")
+	child.SetAttributes(String("k", "v"), Int(KeyInputLength, 4))
+	child.AddEvent("step", Int("n", 1))
+	child.RecordError(nil) // ignored
+	child.RecordError(errors.New("boom"))
+	child.End()
+	child.End() // ending twice records once
+	parent.End()
+
+	spans := rec.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+	c, p := spans[0], spans[1]
+	if c.Name != "child" || p.Name != "parent" {
+		t.Fatalf("Spans recorded in wrong order: %q, %q", c.Name, p.Name)
+	}
+	if p.ParentID != 0 || c.ParentID != p.ID {
+		t.Errorf("Parent linkage: child.ParentID=%d parent.ID=%d parent.ParentID=%d", c.ParentID, p.ID, p.ParentID)
+	}
+	if c.Attributes["k"] != "v" || c.Attributes[KeyInputLength] != 4 || c.Attributes[KeyError] != "boom" {
+		t.Errorf("Unexpected child attributes: %v", c.Attributes)
+	}
+	if len(c.Events) != 1 || c.Events[0].Name != "step" || c.Events[0].Attributes["n"] != 1 {
+		t.Errorf("Unexpected child events: %+v", c.Events)
+	}
+	if c.Err == nil || c.End.Before(c.Start) {
+		t.Errorf("Expected error and End >= Start, got %+v", c)
+	}
+
+	rec.Reset()
+	if len(rec.Spans()) != 0 {
+		t.Error("Reset did not discard spans")
+	}
+}